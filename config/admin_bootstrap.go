@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"property-backend/models"
+	"property-backend/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BootstrapAdmin creates the first administrator on a fresh database.
+//
+// It only runs while nobody holds the admin role and only when
+// BOOTSTRAP_ADMIN_EMAIL and BOOTSTRAP_ADMIN_PASSWORD are set. If a user with
+// that email already exists they are granted the admin role instead.
+func BootstrapAdmin(db *gorm.DB) {
+	email := os.Getenv("BOOTSTRAP_ADMIN_EMAIL")
+	password := os.Getenv("BOOTSTRAP_ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		return
+	}
+
+	var adminRole models.RolesMaster
+	if err := db.Where("role = ?", models.RoleAdmin).First(&adminRole).Error; err != nil {
+		log.Fatal("Admin role missing, run SeedRoles first: ", err)
+	}
+
+	var admins int64
+	if err := db.Model(&models.UserRoles{}).Where("role_id = ?", adminRole.RolesMasterID).Count(&admins).Error; err != nil {
+		log.Fatal("Failed to count admins: ", err)
+	}
+	if admins > 0 {
+		return
+	}
+
+	if len(password) < 8 {
+		log.Fatal("BOOTSTRAP_ADMIN_PASSWORD must be at least 8 characters")
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Where("email = ?", email).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			hashed, err := utils.HashPassword(password)
+			if err != nil {
+				return err
+			}
+			user = models.User{
+				FirstName:      "Admin",
+				LastName:       "User",
+				Email:          email,
+				HashedPassword: hashed,
+				RoleID:         adminRole.RolesMasterID,
+			}
+			// AfterCreate adds the user_roles row
+			return tx.Create(&user).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.UserRoles{UserID: user.UserID, RoleID: adminRole.RolesMasterID}).Error
+	})
+	if err != nil {
+		log.Fatal("Failed to bootstrap admin: ", err)
+	}
+
+	log.Println("✅ Bootstrap admin ready:", email)
+}
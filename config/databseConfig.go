@@ -43,6 +43,9 @@ func ConnectDatabase() {
 
 	log.Println("✅ Database connected")
 
+	// clean up data that would violate new constraints
+	RunPreMigrations(DB)
+
 	// ✅ MIGRATION ORDER MATTERS
 	err = DB.AutoMigrate(
 
@@ -66,9 +69,18 @@ func ConnectDatabase() {
 
 		// property related child tables
 		&models.UserRoles{},
+		&models.UserToken{},
+		&models.LoginAttempt{},
+		&models.AuditLog{},
+		&models.APIKey{},
+		&models.RecoveryCode{},
+		&models.DataCleanupReport{},
 		&models.PropertyLandDetails{},
 		&models.PropertyBuildingDetails{},
 		&models.PropertyTaxDetails{},
+		&models.PropertyValuation{},
+		&models.RentScheduleEntry{},
+		&models.RentPayment{},
 		&models.PropertyOwnershipDetails{},
 		&models.PropertyMedia{},
 	)
@@ -79,6 +91,15 @@ func ConnectDatabase() {
 
 	log.Println("✅ Tables migrated successfully")
 
+	// roles referenced by the authorization policy
+	SeedRoles(DB)
+
+	// data backfills that depend on the migrated schema
+	RunDataMigrations(DB)
+
+	// first administrator on a fresh database
+	BootstrapAdmin(DB)
+
 	// optional seed
 	// SeedLocationFromExcel(DB)
 }
@@ -0,0 +1,235 @@
+package config
+
+import (
+	"fmt"
+	"log"
+
+	"property-backend/models"
+	"property-backend/repositories"
+
+	"gorm.io/gorm"
+)
+
+// RunPreMigrations prepares existing data so AutoMigrate can add new constraints
+func RunPreMigrations(db *gorm.DB) {
+	dedupeUserRoles(db)
+	convertNumericColumns(db)
+}
+
+// RunDataMigrations backfills data after AutoMigrate has brought the schema up to date
+func RunDataMigrations(db *gorm.DB) {
+	backfillUserRoles(db)
+	backfillLandAreas(db)
+	backfillValuations(db)
+	addAgreementOverlapConstraint(db)
+	backfillRentSchedules(db)
+}
+
+// dedupeUserRoles removes duplicate (user_id, role_id) rows before the unique index is created
+func dedupeUserRoles(db *gorm.DB) {
+	if !db.Migrator().HasTable(&models.UserRoles{}) {
+		return
+	}
+	if err := db.Exec(`
+		DELETE FROM user_roles a
+		USING user_roles b
+		WHERE a.user_roles_id > b.user_roles_id
+		  AND a.user_id = b.user_id
+		  AND a.role_id = b.role_id`).Error; err != nil {
+		log.Fatal("Failed to dedupe user_roles: ", err)
+	}
+}
+
+// backfillUserRoles copies every user.role_id into user_roles so the primary
+// role is always part of the user's role set
+func backfillUserRoles(db *gorm.DB) {
+	res := db.Exec(`
+		INSERT INTO user_roles (user_id, role_id)
+		SELECT u.user_id, u.role_id
+		FROM "user" u
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_roles ur
+			WHERE ur.user_id = u.user_id AND ur.role_id = u.role_id
+		)`)
+	if res.Error != nil {
+		log.Fatal("Failed to backfill user_roles: ", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("✅ Backfilled %d user_roles rows from user.role_id", res.RowsAffected)
+	}
+}
+
+// numericColumn is a varchar column that holds a number. idColumn identifies
+// the row in data_cleanup_report.
+type numericColumn struct {
+	table, column, idColumn string
+	precision, scale        int
+}
+
+var numericColumns = []numericColumn{
+	{"property", "value", "property_id", 15, 2},
+	{"property", "income", "property_id", 15, 2},
+	{"assets", "cost", "asset_id", 15, 2},
+	{"property_land_details", "acre", "property_id", 12, 4},
+	{"property_land_details", "gunte", "property_id", 12, 4},
+	{"property_land_details", "karab", "property_id", 12, 4},
+	{"property_building_details", "plot_size", "property_id", 14, 2},
+	{"property_building_details", "built_up_area", "property_id", 14, 2},
+}
+
+// convertNumericColumns turns the varchar amount and area columns into
+// numeric ones before AutoMigrate sees them. Spaces and thousands separators
+// are dropped; anything else that is not a plain number, or is too large for
+// the new column, becomes NULL and is written to data_cleanup_report.
+// Columns that are already numeric are skipped, so this runs once.
+func convertNumericColumns(db *gorm.DB) {
+	if err := db.AutoMigrate(&models.DataCleanupReport{}); err != nil {
+		log.Fatal("Failed to create data_cleanup_report: ", err)
+	}
+	for _, col := range numericColumns {
+		var dataType string
+		if err := db.Raw(`SELECT data_type FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`,
+			col.table, col.column).Scan(&dataType).Error; err != nil {
+			log.Fatal("Failed to inspect ", col.table, ".", col.column, ": ", err)
+		}
+		if dataType != "character varying" && dataType != "text" {
+			continue
+		}
+
+		cleaned := fmt.Sprintf(`regexp_replace("%s", '[[:space:],]', '', 'g')`, col.column)
+		// "?" is avoided because gorm reads it as a bind placeholder
+		pattern := fmt.Sprintf(`^[0-9]{1,%d}(\.[0-9]+){0,1}$`, col.precision-col.scale)
+		var reported int64
+		err := db.Transaction(func(tx *gorm.DB) error {
+			res := tx.Exec(fmt.Sprintf(`
+				INSERT INTO data_cleanup_report (source_table, source_column, row_id, original_value, reason, created_at)
+				SELECT '%[1]s', '%[2]s', "%[3]s", "%[2]s", 'not a number or too large for %[6]s', NOW()
+				FROM "%[1]s"
+				WHERE TRIM(COALESCE("%[2]s", '')) <> '' AND %[4]s !~ '%[5]s'`,
+				col.table, col.column, col.idColumn, cleaned, pattern, col.sqlType()))
+			if res.Error != nil {
+				return res.Error
+			}
+			reported = res.RowsAffected
+			return tx.Exec(fmt.Sprintf(`ALTER TABLE "%s" ALTER COLUMN "%s" TYPE %s USING (CASE WHEN %s ~ '%s' THEN %s::numeric END)`,
+				col.table, col.column, col.sqlType(), cleaned, pattern, cleaned)).Error
+		})
+		if err != nil {
+			log.Fatal("Failed to convert ", col.table, ".", col.column, " to numeric: ", err)
+		}
+		log.Printf("✅ Converted %s.%s to %s (%d unparseable values recorded in data_cleanup_report)",
+			col.table, col.column, col.sqlType(), reported)
+	}
+}
+
+func (c numericColumn) sqlType() string {
+	return fmt.Sprintf("numeric(%d,%d)", c.precision, c.scale)
+}
+
+// backfillLandAreas fills the canonical land areas of rows stored before they existed
+func backfillLandAreas(db *gorm.DB) {
+	n, err := repositories.BackfillLandAreas(db)
+	if err != nil {
+		log.Fatal("Failed to backfill land areas: ", err)
+	}
+	if n > 0 {
+		log.Printf("✅ Computed canonical land area for %d properties", n)
+	}
+}
+
+// backfillValuations starts the valuation history of every property that has
+// a value but no valuations, dating the entry to when the property was added
+func backfillValuations(db *gorm.DB) {
+	res := db.Exec(`
+		INSERT INTO property_valuations (property_id, amount, valued_on, source, notes, created_at)
+		SELECT p.property_id, p.value, p.created_at::date, ?, 'value recorded before valuation history', NOW()
+		FROM property p
+		WHERE p.value IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM property_valuations v WHERE v.property_id = p.property_id)`,
+		models.ValuationSourceManual)
+	if res.Error != nil {
+		log.Fatal("Failed to backfill property valuations: ", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("✅ Started valuation history for %d properties", res.RowsAffected)
+	}
+}
+
+// backfillRentSchedules builds the rent schedule of agreements created before
+// schedules existed
+func backfillRentSchedules(db *gorm.DB) {
+	n, err := repositories.BackfillRentSchedules(db)
+	if err != nil {
+		log.Fatal("Failed to backfill rent schedules: ", err)
+	}
+	if n > 0 {
+		log.Printf("✅ Built rent schedules for %d agreements", n)
+	}
+}
+
+// agreementOverlapConstraint stops two agreements for the same property and
+// unit from covering the same day. It only backs up part of the repository's
+// rule: an agreement without a unit_no covers the whole property and clashes
+// with every unit, which an exclusion on unit_no cannot express, so those
+// clashes are caught by the repository check alone.
+//
+// Rows whose end is not after their start are left out. The API refuses such
+// agreements, so they are zero dates saved before dates were persisted, and
+// tstzrange fails outright on an end before the start.
+const agreementOverlapConstraint = repositories.AgreementOverlapConstraint
+
+// overlappingAgreements pairs each agreement (b) with an earlier one (a) on the
+// same property and unit whose period overlaps it. Rows whose end is not after
+// their start, such as zero dates, are left out as the constraint does.
+const overlappingAgreements = `
+	FROM agreement a
+	JOIN agreement b ON b.property_id = a.property_id AND b.unit_no = a.unit_no AND b.agreement_id > a.agreement_id
+	WHERE a.end_date > a.start_date AND b.end_date > b.start_date
+	  AND a.start_date <= b.end_date AND a.end_date >= b.start_date`
+
+// addAgreementOverlapConstraint adds agreementOverlapConstraint once existing
+// data allows it. Overlaps already in the table are written to
+// data_cleanup_report and the constraint is retried on the next start.
+func addAgreementOverlapConstraint(db *gorm.DB) {
+	var exists bool
+	if err := db.Raw(`SELECT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = ?)`, agreementOverlapConstraint).
+		Scan(&exists).Error; err != nil {
+		log.Fatal("Failed to inspect agreement constraints: ", err)
+	}
+	if exists {
+		return
+	}
+	// equality on plain columns inside a gist index needs btree_gist
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS btree_gist`).Error; err != nil {
+		log.Printf("Skipping %s, the btree_gist extension could not be enabled: %v", agreementOverlapConstraint, err)
+		return
+	}
+
+	var overlaps int64
+	if err := db.Raw(`SELECT COUNT(*)` + overlappingAgreements).Scan(&overlaps).Error; err != nil {
+		log.Fatal("Failed to check for overlapping agreements: ", err)
+	}
+	if overlaps > 0 {
+		if err := db.Exec(`
+			INSERT INTO data_cleanup_report (source_table, source_column, row_id, original_value, reason, created_at)
+			SELECT 'agreement', 'start_date', b.agreement_id, b.start_date::text || ' to ' || b.end_date::text,
+				'overlaps agreement ' || a.agreement_id, NOW()` + overlappingAgreements + `
+			  AND NOT EXISTS (
+				SELECT 1 FROM data_cleanup_report r
+				WHERE r.source_table = 'agreement' AND r.row_id = b.agreement_id
+				  AND r.reason = 'overlaps agreement ' || a.agreement_id)`).Error; err != nil {
+			log.Fatal("Failed to record overlapping agreements: ", err)
+		}
+		log.Printf("Skipping %s: %d overlapping agreements are listed in data_cleanup_report", agreementOverlapConstraint, overlaps)
+		return
+	}
+
+	if err := db.Exec(fmt.Sprintf(`
+		ALTER TABLE agreement ADD CONSTRAINT %s
+		EXCLUDE USING gist (property_id WITH =, unit_no WITH =, tstzrange(start_date, end_date, '[]') WITH &&)
+		WHERE (end_date > start_date)`, agreementOverlapConstraint)).Error; err != nil {
+		log.Fatal("Failed to add ", agreementOverlapConstraint, ": ", err)
+	}
+	log.Printf("✅ Added %s", agreementOverlapConstraint)
+}
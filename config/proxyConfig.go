@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// TrustedProxies returns the proxy addresses or CIDRs listed in
+// TRUSTED_PROXIES (comma separated). By default none are trusted, so
+// X-Forwarded-For is ignored and the client IP used for sign-in throttling is
+// the address of the connection itself.
+func TrustedProxies() ([]string, error) {
+	return parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+}
+
+// parseTrustedProxies splits a comma separated list and rejects entries that
+// are neither an IP address nor a CIDR, so a typo fails at startup instead of
+// silently trusting nothing
+func parseTrustedProxies(list string) ([]string, error) {
+	var proxies []string
+	for _, p := range strings.Split(list, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		if strings.Contains(p, "/") {
+			if _, _, err := net.ParseCIDR(p); err != nil {
+				return nil, fmt.Errorf("invalid proxy CIDR %q", p)
+			}
+		} else if net.ParseIP(p) == nil {
+			return nil, fmt.Errorf("invalid proxy address %q", p)
+		}
+		proxies = append(proxies, p)
+	}
+	return proxies, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		list string
+		want []string
+		ok   bool
+	}{
+		{"unset", "", nil, true},
+		{"single address", "127.0.0.1", []string{"127.0.0.1"}, true},
+		{"addresses and CIDRs with spaces", " 127.0.0.1 , 10.0.0.0/8,::1", []string{"127.0.0.1", "10.0.0.0/8", "::1"}, true},
+		{"empty entries", ",10.0.0.1,,", []string{"10.0.0.1"}, true},
+		{"IPv6 CIDR", "fd00::/8", []string{"fd00::/8"}, true},
+		{"prefix too long", "10.0.0.0/33", nil, false},
+		{"bad network", "10.0.0/8", nil, false},
+		{"hostname", "proxy.internal", nil, false},
+		{"address out of range", "10.0.0.256", nil, false},
+		{"one bad entry among good ones", "127.0.0.1,10.0.0.0/8x", nil, false},
+	} {
+		got, err := parseTrustedProxies(tc.list)
+		if tc.ok != (err == nil) {
+			t.Errorf("%s: error %v, want ok=%t", tc.name, err, tc.ok)
+			continue
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"log"
+
+	"property-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// SeedRoles makes sure every built-in role used by the authorization policy exists in roles_master
+func SeedRoles(db *gorm.DB) {
+	roles := []string{
+		models.RoleAdmin,
+		models.RolePropertyManager,
+		models.RoleAccountant,
+		models.RoleViewer,
+	}
+
+	for _, name := range roles {
+		var role models.RolesMaster
+		if err := db.Where(models.RolesMaster{Role: name}).FirstOrCreate(&role).Error; err != nil {
+			log.Fatal("Failed to seed role ", name, ": ", err)
+		}
+	}
+
+	log.Println("✅ Roles seeded")
+}
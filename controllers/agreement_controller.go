@@ -2,10 +2,14 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"property-backend/models"
+	"property-backend/repositories"
 	"property-backend/services"
 )
 
@@ -21,10 +25,13 @@ func NewAgreementController(svc services.AgreementService) *AgreementController
 
 // AddRentalAgreement godoc
 // @Summary Create a rental agreement
+// @Description start_date and end_date take YYYY-MM-DD or DD-MM-YYYY; the end must be after the start.
+// @Description escalation_type (percentage or fixed) with escalation_value and escalation_interval_months raises the rent from the start date; escalation_cap is the highest monthly rent
 // @Tags Agreements
 // @Accept json
 // @Produce json
 // @Success 201 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{} "Overlaps conflicting_agreement_id"
 // @Router /api/v1/agreements [post]
 func (a *AgreementController) AddRentalAgreement(c *gin.Context) {
 	var req struct {
@@ -35,21 +42,60 @@ func (a *AgreementController) AddRentalAgreement(c *gin.Context) {
 		Deposit    float64 `json:"deposit"`
 		StartDate  string  `json:"start_date" binding:"required"`
 		EndDate    string  `json:"end_date" binding:"required"`
+		UnitNo     string  `json:"unit_no" binding:"max=50"`
+		// RentFrequency is monthly (default) or quarterly; rent is always the monthly figure
+		RentFrequency string `json:"rent_frequency"`
+		// Escalation raises the rent by a percentage or fixed amount every interval, up to the cap
+		EscalationType           string   `json:"escalation_type"`
+		EscalationValue          float64  `json:"escalation_value"`
+		EscalationIntervalMonths int      `json:"escalation_interval_months"`
+		EscalationCap            *float64 `json:"escalation_cap"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	start, ok := dateField(c, "start_date", req.StartDate)
+	if !ok {
+		return
+	}
+	end, ok := dateField(c, "end_date", req.EndDate)
+	if !ok {
+		return
+	}
 	ag := models.Agreement{
-		PropertyID: req.PropertyID,
-		TenantName: req.TenantName,
-		ContactNo:  req.ContactNo,
-		Rent:       req.Rent,
-		Deposit:    req.Deposit,
+		PropertyID:    req.PropertyID,
+		TenantName:    req.TenantName,
+		ContactNo:     req.ContactNo,
+		Rent:          req.Rent,
+		Deposit:       req.Deposit,
+		StartDate:     start,
+		EndDate:       end,
+		UnitNo:        strings.TrimSpace(req.UnitNo),
+		RentFrequency: req.RentFrequency,
+
+		EscalationType:           req.EscalationType,
+		EscalationValue:          req.EscalationValue,
+		EscalationIntervalMonths: req.EscalationIntervalMonths,
+		EscalationCap:            req.EscalationCap,
 	}
-	// parse dates into time.Time when repository expects them (gorm will handle zero values)
 	id, err := a.svc.AddRentalAgreement(context.Background(), &ag)
 	if err != nil {
+		var overlap *repositories.OverlapError
+		switch {
+		case errors.Is(err, services.ErrInvalidAgreementInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, services.ErrPropertyNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.As(err, &overlap):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicting_agreement_id": overlap.AgreementID})
+			return
+		case errors.Is(err, repositories.ErrAgreementOverlap):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -60,13 +106,61 @@ func (a *AgreementController) AddRentalAgreement(c *gin.Context) {
 // @Summary Get all agreements
 // @Tags Agreements
 // @Produce json
-// @Success 200 {array} models.Agreement
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; replaces offset"
+// @Success 200 {object} dto.PageResponse[models.Agreement]
 // @Router /api/v1/agreements [get]
 func (a *AgreementController) GetAllAgreements(c *gin.Context) {
-	ags, err := a.svc.GetAllAgreements(context.Background())
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	ags, info, err := a.svc.GetAllAgreements(context.Background(), page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, ags)
+	c.JSON(http.StatusOK, pageResponse(ags, page, info))
+}
+
+// SetNoticeDate godoc
+// @Summary Record or withdraw notice to vacate
+// @Description A null notice_date withdraws the notice
+// @Tags Agreements
+// @Accept json
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/agreements/{id}/notice [put]
+func (a *AgreementController) SetNoticeDate(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		NoticeDate *string `json:"notice_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var date *time.Time
+	if req.NoticeDate != nil {
+		d, ok := dateField(c, "notice_date", *req.NoticeDate)
+		if !ok {
+			return
+		}
+		date = &d
+	}
+	if err := a.svc.SetNoticeDate(context.Background(), id, date); err != nil {
+		if errors.Is(err, services.ErrAgreementNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/models"
+	"property-backend/repositories"
+	"property-backend/services"
+)
+
+// addAgreementService fails AddRentalAgreement with err; any other method panics
+type addAgreementService struct {
+	services.AgreementService
+	err error
+}
+
+func (s addAgreementService) AddRentalAgreement(ctx context.Context, a *models.Agreement) (int64, error) {
+	return 0, s.err
+}
+
+func TestAddRentalAgreementReportsOverlaps(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"app check names the clash", &repositories.OverlapError{AgreementID: 12}, `"conflicting_agreement_id":12`},
+		{"constraint without a clash found", repositories.ErrAgreementOverlap, `"error":"agreement overlaps an existing agreement"`},
+	} {
+		r := gin.New()
+		r.POST("/agreements", NewAgreementController(addAgreementService{err: tc.err}).AddRentalAgreement)
+
+		body := `{"property_id":1,"tenant_name":"Ravi","rent":12000,"start_date":"2026-01-01","end_date":"2026-12-31","unit_no":"A"}`
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/agreements", strings.NewReader(body)))
+		if w.Code != http.StatusConflict {
+			t.Errorf("%s: status %d, want 409: %s", tc.name, w.Code, w.Body)
+		}
+		if !strings.Contains(w.Body.String(), tc.want) {
+			t.Errorf("%s: body %s, want %s", tc.name, w.Body, tc.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -28,14 +29,14 @@ func NewAssetController(svc services.AssetService) *AssetController {
 // @Router /api/v1/assets [post]
 func (a *AssetController) AddAsset(c *gin.Context) {
 	var req struct {
-		AssetName        string `json:"asset_name" binding:"required"`
-		AssetTypeID      uint   `json:"asset_type_id" binding:"required"`
-		PropertyID       uint   `json:"property_id" binding:"required"`
-		AssetLocation    string `json:"location"`
-		AssetCost        string `json:"cost"`
-		AssetAMCProvider string `json:"amc_provider"`
-		AssetStartDate   string `json:"start_date"`
-		AssetEndDate     string `json:"end_date"`
+		AssetName        string        `json:"asset_name" binding:"required"`
+		AssetTypeID      uint          `json:"asset_type_id" binding:"required"`
+		PropertyID       uint          `json:"property_id" binding:"required"`
+		AssetLocation    string        `json:"location"`
+		AssetCost        *models.Money `json:"cost"`
+		AssetAMCProvider string        `json:"amc_provider"`
+		AssetStartDate   string        `json:"start_date"`
+		AssetEndDate     string        `json:"end_date"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -53,6 +54,10 @@ func (a *AssetController) AddAsset(c *gin.Context) {
 	}
 	id, err := a.svc.AddAsset(context.Background(), &asset)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidAssetInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -63,13 +68,20 @@ func (a *AssetController) AddAsset(c *gin.Context) {
 // @Summary Get all assets
 // @Tags Assets
 // @Produce json
-// @Success 200 {array} models.Asset
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; replaces offset"
+// @Success 200 {object} dto.PageResponse[models.Asset]
 // @Router /api/v1/assets [get]
 func (a *AssetController) GetAllAssets(c *gin.Context) {
-	assets, err := a.svc.GetAllAssets(context.Background())
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	assets, info, err := a.svc.GetAllAssets(context.Background(), page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, assets)
+	c.JSON(http.StatusOK, pageResponse(assets, page, info))
 }
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -27,6 +28,7 @@ func NewAuthController(svc services.AuthService) *AuthController {
 // @Produce json
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /api/v1/auth/signup [post]
 func (a *AuthController) SignUp(c *gin.Context) {
 	var req struct {
@@ -35,7 +37,6 @@ func (a *AuthController) SignUp(c *gin.Context) {
 		Email       string `json:"email" binding:"required,email"`
 		Password    string `json:"password" binding:"required,min=8"`
 		PhoneNumber string `json:"phone_number"`
-		RoleID      uint   `json:"role_id" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -53,10 +54,13 @@ func (a *AuthController) SignUp(c *gin.Context) {
 		Email:          req.Email,
 		HashedPassword: hashed,
 		PhoneNumber:    req.PhoneNumber,
-		RoleID:         req.RoleID,
 	}
 	id, err := a.svc.SignUp(context.Background(), &user)
 	if err != nil {
+		if err == services.ErrEmailTaken {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -65,11 +69,15 @@ func (a *AuthController) SignUp(c *gin.Context) {
 
 // SignIn godoc
 // @Summary Sign in a user
+// @Description Returns a token pair, or an MFA challenge (mfa_required=true) when a second factor is needed.
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} services.AuthTokens
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
 // @Router /api/v1/auth/signin [post]
 func (a *AuthController) SignIn(c *gin.Context) {
 	var req struct {
@@ -80,15 +88,242 @@ func (a *AuthController) SignIn(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	user, err := a.svc.SignIn(context.Background(), req.Email, req.Password)
+	result, err := a.svc.SignIn(context.Background(), req.Email, req.Password, c.ClientIP())
 	if err != nil {
-		// treat invalid credentials distinctly
-		if err == services.ErrInvalidCredentials {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		writeSignInError(c, err)
+		return
+	}
+	if result.Challenge != nil {
+		c.JSON(http.StatusOK, result.Challenge)
+		return
+	}
+	c.JSON(http.StatusOK, result.Tokens)
+}
+
+// SignInMFA godoc
+// @Summary Complete sign in with a TOTP or recovery code
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.AuthTokens
+// @Failure 401 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Router /api/v1/auth/signin/2fa [post]
+func (a *AuthController) SignInMFA(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tokens, err := a.svc.CompleteMFASignIn(context.Background(), req.ChallengeToken, req.Code, c.ClientIP())
+	if err != nil {
+		writeSignInError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// BeginMFAEnrollment godoc
+// @Summary Start the 2FA enrollment required by a role policy
+// @Description Uses the challenge token from a sign in that returned enrollment_required=true.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.MFAEnrollment
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/signin/2fa/enroll [post]
+func (a *AuthController) BeginMFAEnrollment(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	enrollment, err := a.svc.BeginMFAEnrollment(context.Background(), req.ChallengeToken, c.ClientIP())
+	if err != nil {
+		writeSignInError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// CompleteMFAEnrollment godoc
+// @Summary Confirm the required 2FA enrollment and sign in
+// @Description Returns the token pair plus one-time recovery codes.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/signin/2fa/enroll/confirm [post]
+func (a *AuthController) CompleteMFAEnrollment(c *gin.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tokens, codes, err := a.svc.CompleteMFAEnrollment(context.Background(), req.ChallengeToken, req.Code, c.ClientIP())
+	if err != nil {
+		writeSignInError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens, "recovery_codes": codes})
+}
+
+// Refresh godoc
+// @Summary Exchange a refresh token for a new token pair
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.AuthTokens
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/auth/refresh [post]
+func (a *AuthController) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tokens, err := a.svc.Refresh(context.Background(), req.RefreshToken)
+	if err != nil {
+		if err == services.ErrInvalidToken || err == services.ErrAccountDisabled {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err == services.ErrMFAEnrollmentRequired {
+			c.JSON(http.StatusForbidden, gin.H{"error": "two-factor enrollment required, sign in again"})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, tokens)
+}
+
+// ForgotPassword godoc
+// @Summary Email a password reset link
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 202 {object} map[string]interface{}
+// @Router /api/v1/auth/forgot-password [post]
+func (a *AuthController) ForgotPassword(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := a.svc.ForgotPassword(context.Background(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email is registered, a reset link has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary Set a new password using a reset token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/auth/reset-password [post]
+func (a *AuthController) ResetPassword(c *gin.Context) {
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required,min=8"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := a.svc.ResetPassword(context.Background(), req.Token, req.Password); err != nil {
+		if err == services.ErrInvalidToken {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "password updated"})
+}
+
+// VerifyEmail godoc
+// @Summary Confirm an email address using a verification token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/auth/verify-email [post]
+func (a *AuthController) VerifyEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := a.svc.VerifyEmail(context.Background(), req.Token); err != nil {
+		if err == services.ErrInvalidToken {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "email verified"})
+}
+
+// ResendVerification godoc
+// @Summary Send a new email verification link
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 202 {object} map[string]interface{}
+// @Router /api/v1/auth/resend-verification [post]
+func (a *AuthController) ResendVerification(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := a.svc.ResendVerification(context.Background(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the email is registered and unverified, a verification link has been sent"})
+}
+
+func writeSignInError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials):
+		// treat invalid credentials distinctly
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+	case errors.Is(err, services.ErrInvalidMFACode), errors.Is(err, services.ErrInvalidToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAccountDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrMFAAlreadyEnabled), errors.Is(err, services.ErrMFANotEnrolling):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAccountLocked):
+		c.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTooManyAttempts):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,7 @@ func NewContractController(svc services.ContractService) *ContractController {
 
 // AddContract godoc
 // @Summary Create a contract
+// @Description start_date and end_date take YYYY-MM-DD or DD-MM-YYYY; the end must be after the start
 // @Tags Contracts
 // @Accept json
 // @Produce json
@@ -33,8 +35,8 @@ func (ctr *ContractController) AddContract(c *gin.Context) {
 		RelatedTo      string  `json:"related_to"`
 		Cost           float64 `json:"cost"`
 		Provider       string  `json:"provider"`
-		StartDate      string  `json:"start_date"`
-		EndDate        string  `json:"end_date"`
+		StartDate      string  `json:"start_date" binding:"required"`
+		EndDate        string  `json:"end_date" binding:"required"`
 		Terms          string  `json:"terms"`
 		AssetID        uint    `json:"asset_id" binding:"required"`
 	}
@@ -42,17 +44,31 @@ func (ctr *ContractController) AddContract(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	start, ok := dateField(c, "start_date", req.StartDate)
+	if !ok {
+		return
+	}
+	end, ok := dateField(c, "end_date", req.EndDate)
+	if !ok {
+		return
+	}
 	co := models.Contract{
 		Name:           req.Name,
 		ContractTypeID: req.ContractTypeID,
 		RelatedTo:      req.RelatedTo,
 		Cost:           req.Cost,
 		Provider:       req.Provider,
+		StartDate:      start,
+		EndDate:        end,
 		Terms:          req.Terms,
 		AssetID:        req.AssetID,
 	}
 	id, err := ctr.svc.AddContract(context.Background(), &co)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidContractInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -63,43 +79,64 @@ func (ctr *ContractController) AddContract(c *gin.Context) {
 // @Summary Get all contracts
 // @Tags Contracts
 // @Produce json
-// @Success 200 {array} models.Contract
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; replaces offset"
+// @Success 200 {object} dto.PageResponse[models.Contract]
 // @Router /api/v1/contracts [get]
 func (ctr *ContractController) GetAllContracts(c *gin.Context) {
-	contracts, err := ctr.svc.GetAllContracts(context.Background())
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	contracts, info, err := ctr.svc.GetAllContracts(context.Background(), page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, contracts)
+	c.JSON(http.StatusOK, pageResponse(contracts, page, info))
 }
 
 // GetLeaseContracts godoc
 // @Summary Get all lease contracts
 // @Tags Contracts
 // @Produce json
-// @Success 200 {array} models.Contract
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; replaces offset"
+// @Success 200 {object} dto.PageResponse[models.Contract]
 // @Router /api/v1/contracts/lease [get]
 func (ctr *ContractController) GetLeaseContracts(c *gin.Context) {
-	contracts, err := ctr.svc.GetContractsByType(context.Background(), "lease")
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	contracts, info, err := ctr.svc.GetContractsByType(context.Background(), "lease", page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, contracts)
+	c.JSON(http.StatusOK, pageResponse(contracts, page, info))
 }
 
 // GetAMCContracts godoc
 // @Summary Get all AMC contracts
 // @Tags Contracts
 // @Produce json
-// @Success 200 {array} models.Contract
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; replaces offset"
+// @Success 200 {object} dto.PageResponse[models.Contract]
 // @Router /api/v1/contracts/amc [get]
 func (ctr *ContractController) GetAMCContracts(c *gin.Context) {
-	contracts, err := ctr.svc.GetContractsByType(context.Background(), "amc")
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	contracts, info, err := ctr.svc.GetContractsByType(context.Background(), "amc", page)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, contracts)
+	c.JSON(http.StatusOK, pageResponse(contracts, page, info))
 }
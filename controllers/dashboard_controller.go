@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/services"
+)
+
+// DashboardController handles the portfolio dashboard endpoint
+type DashboardController struct {
+	svc services.DashboardService
+}
+
+// NewDashboardController creates a new DashboardController
+func NewDashboardController(svc services.DashboardService) *DashboardController {
+	return &DashboardController{svc: svc}
+}
+
+// Summary godoc
+// @Summary Portfolio dashboard summary
+// @Description Property counts by type and district, portfolio value, rental income, occupancy, upcoming expiries and unpaid tax in one call
+// @Tags Dashboard
+// @Produce json
+// @Success 200 {object} dto.DashboardSummary
+// @Router /api/v1/dashboard/summary [get]
+func (d *DashboardController) Summary(c *gin.Context) {
+	summary, err := d.svc.Summary(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
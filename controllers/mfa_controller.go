@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/middleware"
+	"property-backend/services"
+)
+
+// MFAController handles two-factor enrollment for the current user and the
+// administrator 2FA controls
+type MFAController struct {
+	svc services.MFAService
+}
+
+// NewMFAController creates a new MFAController
+func NewMFAController(svc services.MFAService) *MFAController {
+	return &MFAController{svc: svc}
+}
+
+// GetMyMFAStatus godoc
+// @Summary Show the current user's two-factor status
+// @Tags Two-Factor
+// @Produce json
+// @Success 200 {object} services.MFAStatus
+// @Router /api/v1/users/me/2fa [get]
+func (m *MFAController) GetMyMFAStatus(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+	status, err := m.svc.Status(context.Background(), userID)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// BeginEnrollment godoc
+// @Summary Generate a TOTP secret and provisioning URI for the current user
+// @Description The URI can be rendered as a QR code; 2FA is enabled once a code is confirmed.
+// @Tags Two-Factor
+// @Produce json
+// @Success 200 {object} services.MFAEnrollment
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/users/me/2fa/enroll [post]
+func (m *MFAController) BeginEnrollment(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+	enrollment, err := m.svc.BeginEnrollment(context.Background(), userID)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmEnrollment godoc
+// @Summary Enable 2FA by confirming a code from the authenticator app
+// @Description Returns one-time recovery codes; they are not shown again.
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/users/me/2fa/confirm [post]
+func (m *MFAController) ConfirmEnrollment(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := middleware.CurrentUserID(c)
+	codes, err := m.svc.ConfirmEnrollment(context.Background(), userID, req.Code)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Replace the current user's recovery codes
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/users/me/2fa/recovery-codes [post]
+func (m *MFAController) RegenerateRecoveryCodes(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := middleware.CurrentUserID(c)
+	codes, err := m.svc.RegenerateRecoveryCodes(context.Background(), userID, req.Code)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// DisableMFA godoc
+// @Summary Turn off 2FA for the current user
+// @Tags Two-Factor
+// @Accept json
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/users/me/2fa/disable [post]
+func (m *MFAController) DisableMFA(c *gin.Context) {
+	var req struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := middleware.CurrentUserID(c)
+	if err := m.svc.Disable(context.Background(), userID, req.Password, req.Code); err != nil {
+		writeMFAError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ResetUserMFA godoc
+// @Summary Remove 2FA from a user who lost their authenticator (admin only)
+// @Tags Two-Factor
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/{id}/2fa [delete]
+func (m *MFAController) ResetUserMFA(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	if err := m.svc.Reset(context.Background(), actorID, userID); err != nil {
+		writeMFAError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetRolePolicy godoc
+// @Summary Require (or stop requiring) 2FA for every user holding a role (admin only)
+// @Tags Two-Factor
+// @Accept json
+// @Produce json
+// @Param id path int true "Role ID"
+// @Success 200 {object} models.RolesMaster
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/roles/{id}/2fa-policy [put]
+func (m *MFAController) SetRolePolicy(c *gin.Context) {
+	roleID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Require2FA *bool `json:"require_2fa" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	role, err := m.svc.SetRolePolicy(context.Background(), actorID, roleID, *req.Require2FA)
+	if err != nil {
+		writeMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+func writeMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMFACode), errors.Is(err, services.ErrMFANotEnabled):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "password is incorrect"})
+	case errors.Is(err, services.ErrMFAAlreadyEnabled), errors.Is(err, services.ErrMFANotEnrolling), errors.Is(err, services.ErrMFARequiredByPolicy):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		writeUserError(c, err)
+	}
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/dto"
+	"property-backend/repositories"
+	"property-backend/utils"
+)
+
+// uintParam reads a positive integer path parameter, writing a 400 response when it is invalid
+func uintParam(c *gin.Context, name string) (uint, bool) {
+	v, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || v == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return uint(v), true
+}
+
+// pageParams reads ?limit=, ?offset= and ?cursor=, applying the default and
+// upper limit. A cursor replaces the offset.
+func pageParams(c *gin.Context) (repositories.Page, bool) {
+	page := repositories.Page{Limit: repositories.DefaultPageLimit}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return page, false
+		}
+		page.Limit = min(n, repositories.MaxPageLimit)
+	}
+	if v := c.Query("cursor"); v != "" {
+		id, err := repositories.DecodeCursor(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return page, false
+		}
+		page.AfterID = id
+		return page, true
+	}
+	if v := c.Query("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+			return page, false
+		}
+		page.Offset = n
+	}
+	return page, true
+}
+
+// pageResponse wraps one page of items in the list envelope
+func pageResponse[T any](items []T, page repositories.Page, info repositories.PageInfo) dto.PageResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return dto.PageResponse[T]{
+		Items:      items,
+		Total:      info.Total,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		NextCursor: info.NextCursor,
+	}
+}
+
+// uintQuery reads an optional positive integer query parameter; 0 means absent
+func uintQuery(c *gin.Context, name string) (uint, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || n == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return uint(n), true
+}
+
+// floatQuery reads an optional numeric query parameter
+func floatQuery(c *gin.Context, name string) (*float64, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, true
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return nil, false
+	}
+	return &f, true
+}
+
+// boolQuery reads an optional boolean query parameter
+func boolQuery(c *gin.Context, name string) (*bool, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, true
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return nil, false
+	}
+	return &b, true
+}
+
+// timeQuery reads an optional YYYY-MM-DD or RFC3339 query parameter. With
+// endOfDay set, a plain date is moved to the start of the next day so it can
+// be used as an exclusive upper bound that still includes the whole day.
+func timeQuery(c *gin.Context, name string, endOfDay bool) (*time.Time, bool) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, true
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, true
+	}
+	t, err := time.ParseInLocation(time.DateOnly, v, time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name + ", expected YYYY-MM-DD or RFC3339"})
+		return nil, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, true
+}
+
+// dateField parses a date from a request body field named name, writing a 400
+// response when it is not a valid date
+func dateField(c *gin.Context, name, value string) (time.Time, bool) {
+	t, err := utils.ParseDate(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name + ": " + err.Error()})
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -2,11 +2,17 @@ package controllers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"property-backend/dto"
+	"property-backend/middleware"
+	"property-backend/models"
+	"property-backend/repositories"
 	"property-backend/services"
+	"property-backend/utils"
 )
 
 // PropertyController handles property-related endpoints
@@ -36,157 +42,418 @@ func (p *PropertyController) TotalProperties(c *gin.Context) {
 }
 
 // ActiveRentalPropertyCount godoc
-// @Summary Get active rental properties count
+// @Summary Count properties with at least one running agreement
+// @Description Each property is counted once, whether let, partially let or in its notice period
 // @Tags Properties
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Router /api/v1/properties/active-rental/count [get]
 func (p *PropertyController) ActiveRentalPropertyCount(c *gin.Context) {
-	count, err := p.svc.ActiveRentalCount(context.Background())
+	counts, err := p.svc.OccupancyCounts(context.Background())
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	var count int64
+	for status, n := range counts {
+		if status != models.OccupancyVacant {
+			count += n
+		}
+	}
 	c.JSON(http.StatusOK, gin.H{"active_rental_count": count})
 }
 
+// OccupancyCounts godoc
+// @Summary Count properties by occupancy status
+// @Description Statuses are vacant, let, partially_let and notice_period, computed from the agreements running today
+// @Tags Properties
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/properties/occupancy [get]
+func (p *PropertyController) OccupancyCounts(c *gin.Context) {
+	counts, err := p.svc.OccupancyCounts(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+	c.JSON(http.StatusOK, gin.H{"total": total, "by_status": counts})
+}
+
 // AddProperty godoc
 // @Summary Add a new property with all details
 // @Tags Properties
 // @Accept json
 // @Produce json
+// @Param property body dto.PropertyCreate true "Property with its address and detail sections"
 // @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /api/v1/properties [post]
 func (p *PropertyController) AddProperty(c *gin.Context) {
-	var req struct {
-		// Property basic info
-		PropertyName   string `json:"property_name" binding:"required"`
-		PropertyTypeID uint   `json:"property_type_id"`
-		PropertyTypeName string `json:"property_type_name"`
-		Value          string `json:"value"`
-		Income         string `json:"income"`
-		OriginalDeed   string `json:"original_deed"`
-		UserID         uint   `json:"user_id" binding:"required"`
-
-		// Address information
-		CountryName    string `json:"country_name"`
-		StateName      string `json:"state_name"`
-		DistrictName   string `json:"district_name"`
-		TalukName      string `json:"taluk_name"`
-		Hobli          string `json:"hobli"`
-		Village        string `json:"village"`
-		StreetAddress  string `json:"street_address"`
-		Pincode        string `json:"pincode"`
-		LatCoordinate  string `json:"lat_coordinate"`
-		LongCoordinate string `json:"long_coordinate"`
-
-		// Land details
-		Rtc        string `json:"rtc"`
-		Ec         string `json:"ec"`
-		SyNo       string `json:"syno"`
-		KhataNo    string `json:"khatano"`
-		MrNo       string `json:"mrno"`
-		Acre       string `json:"acre"`
-		Gunte      string `json:"gunte"`
-		Karab      string `json:"karab"`
-		Converted  string `json:"converted"`
-		Purpose    string `json:"purpose"`
-		KhuskiTari string `json:"khuskitari"`
-
-		// Tax details
-		ReceiptNo   string  `json:"receipt_no"`
-		PrevAmount  float64 `json:"prev_amount"`
-		CurrAmount  float64 `json:"curr_amount"`
-		ReceiptLink string  `json:"receipt_link"`
-
-		// Ownership details
-		ReceivedFrom        string `json:"received_from"`
-		AcquisitionType     string `json:"acquisition_type"`
-		RegistrationDetails string `json:"registration_details"`
-		Title               string `json:"title"`
-		Incharge            string `json:"incharge"`
-		PhoneNumber         string `json:"phone_number"`
-
-		// Building details
-		PlotSize           string `json:"plot_size"`
-		BuiltUpArea        string `json:"built_up_area"`
-		YearOfConstruction string `json:"year_of_construction"`
-		ApplicationNo      string `json:"application_no"`
-
-		// Media
-		ScannedDeedLink string `json:"scanned_deed_link"`
-		PhotoLink       string `json:"photo_link"`
-		Remarks         string `json:"remarks"`
-	}
-
+	var req dto.PropertyCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Convert struct to map for service processing
-	reqBytes, err := json.Marshal(req)
+	// the owner is always the caller, never taken from the body
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
+		return
+	}
+
+	id, err := p.svc.AddProperty(context.Background(), userID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process request"})
+		writePropertyError(c, err)
 		return
 	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
 
-	var reqMap map[string]interface{}
-	if err := json.Unmarshal(reqBytes, &reqMap); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process request"})
+// ListProperties godoc
+// @Summary Search properties
+// @Tags Properties
+// @Produce json
+// @Param q query string false "Match on property name"
+// @Param type query string false "Property type name"
+// @Param property_type_id query int false "Property type ID"
+// @Param state query string false "State name"
+// @Param district query string false "District name"
+// @Param taluk query string false "Taluk name"
+// @Param village query string false "Village"
+// @Param pincode query string false "Pincode"
+// @Param user_id query int false "Owning user"
+// @Param min_value query number false "Minimum value"
+// @Param max_value query number false "Maximum value"
+// @Param tax_paid query bool false "Tax paid status"
+// @Param converted query bool false "Converted land"
+// @Param acquisition_type query string false "Acquisition type"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD or RFC3339)"
+// @Param created_to query string false "Created on or before (YYYY-MM-DD or RFC3339)"
+// @Param occupancy query string false "Comma-separated occupancy statuses: vacant, let, partially_let, notice_period"
+// @Param sort query string false "id, name, created_at or value; prefix with - for descending"
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; only with the default sort"
+// @Param area_unit query string false "Report areas in acre, gunta, hectare, sqft or sqm (default)"
+// @Success 200 {object} dto.PageResponse[models.Property]
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/properties [get]
+func (p *PropertyController) ListProperties(c *gin.Context) {
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	filter, ok := propertyFilter(c)
+	if !ok {
 		return
 	}
+	unit, ok := areaUnitQuery(c)
+	if !ok {
+		return
+	}
+	props, info, err := p.svc.SearchProperties(context.Background(), filter, page)
+	if err != nil {
+		writePropertyError(c, err)
+		return
+	}
+	for i := range props {
+		services.SetAreaSummary(&props[i], unit)
+	}
+	c.JSON(http.StatusOK, pageResponse(props, page, info))
+}
 
-	id, err := p.svc.AddProperty(context.Background(), reqMap)
+// propertyFilter reads the search query parameters, writing a 400 response when one is malformed
+func propertyFilter(c *gin.Context) (repositories.PropertyFilter, bool) {
+	f := repositories.PropertyFilter{
+		Search:          c.Query("q"),
+		PropertyType:    c.Query("type"),
+		State:           c.Query("state"),
+		District:        c.Query("district"),
+		Taluk:           c.Query("taluk"),
+		Village:         c.Query("village"),
+		Pincode:         c.Query("pincode"),
+		AcquisitionType: c.Query("acquisition_type"),
+		Sort:            c.Query("sort"),
+	}
+	// occupancy=vacant,partially_let matches either status
+	if v := c.Query("occupancy"); v != "" {
+		for _, status := range strings.Split(v, ",") {
+			f.Occupancy = append(f.Occupancy, strings.TrimSpace(status))
+		}
+	}
+	var ok bool
+	if f.PropertyTypeID, ok = uintQuery(c, "property_type_id"); !ok {
+		return f, false
+	}
+	if f.UserID, ok = uintQuery(c, "user_id"); !ok {
+		return f, false
+	}
+	if f.MinValue, ok = floatQuery(c, "min_value"); !ok {
+		return f, false
+	}
+	if f.MaxValue, ok = floatQuery(c, "max_value"); !ok {
+		return f, false
+	}
+	if f.TaxPaid, ok = boolQuery(c, "tax_paid"); !ok {
+		return f, false
+	}
+	if f.Converted, ok = boolQuery(c, "converted"); !ok {
+		return f, false
+	}
+	if f.CreatedFrom, ok = timeQuery(c, "created_from", false); !ok {
+		return f, false
+	}
+	if f.CreatedTo, ok = timeQuery(c, "created_to", true); !ok {
+		return f, false
+	}
+	return f, true
+}
+
+// GetProperty godoc
+// @Summary Get a property with its address hierarchy and all detail tables
+// @Tags Properties
+// @Produce json
+// @Param id path int true "Property ID"
+// @Param area_unit query string false "Report areas in acre, gunta, hectare, sqft or sqm (default)"
+// @Success 200 {object} models.Property
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/properties/{id} [get]
+func (p *PropertyController) GetProperty(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	unit, ok := areaUnitQuery(c)
+	if !ok {
+		return
+	}
+	prop, err := p.svc.GetProperty(context.Background(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writePropertyError(c, err)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"id": id})
+	services.SetAreaSummary(prop, unit)
+	c.JSON(http.StatusOK, prop)
+}
+
+// UpdateProperty godoc
+// @Summary Replace a property and all its detail sections
+// @Description Absent fields and sections are cleared, except value, which is kept; property_name, the property type and a full address location are required.
+// @Tags Properties
+// @Accept json
+// @Produce json
+// @Param id path int true "Property ID"
+// @Param property body dto.PropertyUpdate true "Property"
+// @Success 200 {object} models.Property
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/properties/{id} [put]
+func (p *PropertyController) UpdateProperty(c *gin.Context) {
+	p.updateProperty(c, true)
 }
 
-// AgriculturalLandProperties godoc
-// @Summary List agricultural land properties
+// PatchProperty godoc
+// @Summary Partially update a property and any of its detail sections
+// @Description Only fields present in the body are changed; missing detail rows are created.
 // @Tags Properties
+// @Accept json
 // @Produce json
-// @Success 200 {array} models.Property
-// @Router /api/v1/properties/agricultural [get]
-func (p *PropertyController) AgriculturalLandProperties(c *gin.Context) {
-	props, err := p.svc.ListByType(context.Background(), "agricultural")
+// @Param id path int true "Property ID"
+// @Param property body dto.PropertyUpdate true "Fields to change"
+// @Success 200 {object} models.Property
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/properties/{id} [patch]
+func (p *PropertyController) PatchProperty(c *gin.Context) {
+	p.updateProperty(c, false)
+}
+
+func (p *PropertyController) updateProperty(c *gin.Context, replace bool) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	unit, ok := areaUnitQuery(c)
+	if !ok {
+		return
+	}
+	var req dto.PropertyUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	prop, err := p.svc.UpdateProperty(context.Background(), actorID, id, &req, replace)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writePropertyError(c, err)
+		return
+	}
+	services.SetAreaSummary(prop, unit)
+	c.JSON(http.StatusOK, prop)
+}
+
+// DeleteProperty godoc
+// @Summary Soft-delete a property
+// @Description Refused with 409 while an agreement that has not ended, or any asset, references the property.
+// @Tags Properties
+// @Param id path int true "Property ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/properties/{id} [delete]
+func (p *PropertyController) DeleteProperty(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := p.svc.DeleteProperty(context.Background(), id); err != nil {
+		writePropertyError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, props)
+	c.Status(http.StatusNoContent)
 }
 
-// ResidentialLandProperties godoc
-// @Summary List residential land properties
+// RestoreProperty godoc
+// @Summary Restore a soft-deleted property
 // @Tags Properties
 // @Produce json
-// @Success 200 {array} models.Property
-// @Router /api/v1/properties/residential [get]
-func (p *PropertyController) ResidentialLandProperties(c *gin.Context) {
-	props, err := p.svc.ListByType(context.Background(), "residential")
+// @Param id path int true "Property ID"
+// @Success 200 {object} models.Property
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/properties/{id}/restore [post]
+func (p *PropertyController) RestoreProperty(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	prop, err := p.svc.RestoreProperty(context.Background(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writePropertyError(c, err)
+		return
+	}
+	services.SetAreaSummary(prop, utils.AreaSquareMetre)
+	c.JSON(http.StatusOK, prop)
+}
+
+// PurgeProperty godoc
+// @Summary Permanently remove a soft-deleted property (admin only)
+// @Description Deletes the detail rows and address as well. Refused while any agreement or asset references the property.
+// @Tags Properties
+// @Param id path int true "Property ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/properties/{id}/purge [delete]
+func (p *PropertyController) PurgeProperty(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := p.svc.PurgeProperty(context.Background(), id); err != nil {
+		writePropertyError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddValuation godoc
+// @Summary Record a valuation of a property
+// @Description The property's value becomes the amount of its latest valuation by date.
+// @Tags Properties
+// @Accept json
+// @Produce json
+// @Param id path int true "Property ID"
+// @Success 201 {object} models.PropertyValuation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/properties/{id}/valuations [post]
+func (p *PropertyController) AddValuation(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
 		return
 	}
-	c.JSON(http.StatusOK, props)
+	var req struct {
+		Amount   *models.Money `json:"amount" binding:"required"`
+		ValuedOn string        `json:"valued_on" binding:"required"`
+		Source   string        `json:"source" binding:"required"`
+		Notes    string        `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	valuedOn, ok := dateField(c, "valued_on", req.ValuedOn)
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	valuation := models.PropertyValuation{
+		PropertyID: id,
+		Amount:     *req.Amount,
+		ValuedOn:   valuedOn,
+		Source:     req.Source,
+		Notes:      req.Notes,
+		CreatedBy:  actorID,
+	}
+	if err := p.svc.AddValuation(context.Background(), &valuation); err != nil {
+		writePropertyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, valuation)
 }
 
-// CommercialLandProperties godoc
-// @Summary List commercial land properties
+// ListValuations godoc
+// @Summary List a property's valuation history, newest first
 // @Tags Properties
 // @Produce json
-// @Success 200 {array} models.Property
-// @Router /api/v1/properties/commercial [get]
-func (p *PropertyController) CommercialLandProperties(c *gin.Context) {
-	props, err := p.svc.ListByType(context.Background(), "commercial")
+// @Param id path int true "Property ID"
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Success 200 {object} dto.PageResponse[models.PropertyValuation]
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/properties/{id}/valuations [get]
+func (p *PropertyController) ListValuations(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	valuations, info, err := p.svc.ListValuations(context.Background(), id, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writePropertyError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, props)
+	c.JSON(http.StatusOK, pageResponse(valuations, page, info))
+}
+
+// areaUnitQuery reads ?area_unit=, writing a 400 response when it is unknown
+func areaUnitQuery(c *gin.Context) (utils.AreaUnit, bool) {
+	unit, err := utils.ParseAreaUnit(c.Query("area_unit"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return unit, true
+}
+
+func writePropertyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrPropertyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPropertyInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPropertyInUse), errors.Is(err, services.ErrPropertyNotDeleted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/middleware"
+	"property-backend/models"
+	"property-backend/services"
+)
+
+// RentController handles rent schedule, payment and arrears endpoints
+type RentController struct {
+	svc services.RentService
+}
+
+// NewRentController creates a new RentController
+func NewRentController(svc services.RentService) *RentController {
+	return &RentController{svc: svc}
+}
+
+// Schedule godoc
+// @Summary Rent schedule of an agreement
+// @Description Billing periods with payments applied to the oldest rent first
+// @Tags Rent
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/agreements/{id}/schedule [get]
+func (r *RentController) Schedule(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	lines, err := r.svc.Schedule(context.Background(), id)
+	if err != nil {
+		writeRentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"agreement_id": id, "schedule": lines})
+}
+
+// Ledger godoc
+// @Summary Rent ledger of an agreement
+// @Description Rent charged and payments received with the running balance and arrears
+// @Tags Rent
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Success 200 {object} dto.RentLedger
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/agreements/{id}/ledger [get]
+func (r *RentController) Ledger(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	ledger, err := r.svc.Ledger(context.Background(), id)
+	if err != nil {
+		writeRentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ledger)
+}
+
+// AddPayment godoc
+// @Summary Record rent received against an agreement
+// @Tags Rent
+// @Accept json
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Success 201 {object} models.RentPayment
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/agreements/{id}/payments [post]
+func (r *RentController) AddPayment(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		Amount    *models.Money `json:"amount" binding:"required"`
+		PaidOn    string        `json:"paid_on" binding:"required"`
+		Mode      string        `json:"mode"`
+		Reference string        `json:"reference" binding:"max=100"`
+		Notes     string        `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	paidOn, ok := dateField(c, "paid_on", req.PaidOn)
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	payment := models.RentPayment{
+		AgreementID: id,
+		Amount:      *req.Amount,
+		PaidOn:      paidOn,
+		Mode:        req.Mode,
+		Reference:   req.Reference,
+		Notes:       req.Notes,
+		CreatedBy:   actorID,
+	}
+	if err := r.svc.AddPayment(context.Background(), &payment); err != nil {
+		writeRentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, payment)
+}
+
+// Overdue godoc
+// @Summary Agreements with overdue rent across the portfolio
+// @Tags Rent
+// @Produce json
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Success 200 {object} dto.PageResponse[dto.OverdueRent]
+// @Router /api/v1/rent/overdue [get]
+func (r *RentController) Overdue(c *gin.Context) {
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	rows, info, err := r.svc.Overdue(context.Background(), page)
+	if err != nil {
+		writeRentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pageResponse(rows, page, info))
+}
+
+// EffectiveRent godoc
+// @Summary Monthly rent of an agreement on a date
+// @Description The rent after the escalations that have taken effect by the date, and the next escalation
+// @Tags Rent
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Param date query string false "YYYY-MM-DD or DD-MM-YYYY within the agreement (default today)"
+// @Success 200 {object} dto.EffectiveRent
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/agreements/{id}/effective-rent [get]
+func (r *RentController) EffectiveRent(c *gin.Context) {
+	id, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	on := time.Now()
+	if raw := c.Query("date"); raw != "" {
+		if on, ok = dateField(c, "date", raw); !ok {
+			return
+		}
+	}
+	rent, err := r.svc.EffectiveRent(context.Background(), id, on)
+	if err != nil {
+		writeRentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rent)
+}
+
+func writeRentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAgreementNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPaymentInput), errors.Is(err, services.ErrInvalidRentQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/dto"
+	"property-backend/middleware"
+	"property-backend/services"
+)
+
+// ServiceAccountController handles service account and API key endpoints
+type ServiceAccountController struct {
+	svc services.ServiceAccountService
+}
+
+// NewServiceAccountController creates a new ServiceAccountController
+func NewServiceAccountController(svc services.ServiceAccountService) *ServiceAccountController {
+	return &ServiceAccountController{svc: svc}
+}
+
+type apiKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+func (r apiKeyRequest) permissions() []services.Permission {
+	perms := make([]services.Permission, 0, len(r.Scopes))
+	for _, s := range r.Scopes {
+		perms = append(perms, services.Permission(s))
+	}
+	return perms
+}
+
+// CreateServiceAccount godoc
+// @Summary Create a service account and its first API key (admin only)
+// @Description The plaintext key is only returned in this response.
+// @Tags Service Accounts
+// @Accept json
+// @Produce json
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/v1/service-accounts [post]
+func (s *ServiceAccountController) CreateServiceAccount(c *gin.Context) {
+	var req apiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	user, issued, err := s.svc.CreateServiceAccount(context.Background(), actorID, req.Name, req.permissions())
+	if err != nil {
+		writeServiceAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"service_account": dto.NewUserResponse(user),
+		"api_key":         issuedKeyResponse(issued),
+	})
+}
+
+// ListServiceAccounts godoc
+// @Summary List service accounts (admin only)
+// @Tags Service Accounts
+// @Produce json
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; replaces offset"
+// @Success 200 {object} dto.PageResponse[dto.UserResponse]
+// @Router /api/v1/service-accounts [get]
+func (s *ServiceAccountController) ListServiceAccounts(c *gin.Context) {
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	users, info, err := s.svc.ListServiceAccounts(context.Background(), page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pageResponse(dto.NewUserResponses(users), page, info))
+}
+
+// CreateKey godoc
+// @Summary Issue an additional API key for a service account (admin only)
+// @Tags Service Accounts
+// @Accept json
+// @Produce json
+// @Param id path int true "Service account user ID"
+// @Success 201 {object} dto.IssuedAPIKeyResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/service-accounts/{id}/keys [post]
+func (s *ServiceAccountController) CreateKey(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req apiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	issued, err := s.svc.CreateKey(context.Background(), actorID, userID, req.Name, req.permissions())
+	if err != nil {
+		writeServiceAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, issuedKeyResponse(issued))
+}
+
+// ListKeys godoc
+// @Summary List a service account's API keys, including revoked ones (admin only)
+// @Tags Service Accounts
+// @Produce json
+// @Param id path int true "Service account user ID"
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/service-accounts/{id}/keys [get]
+func (s *ServiceAccountController) ListKeys(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	keys, err := s.svc.ListKeys(context.Background(), userID)
+	if err != nil {
+		writeServiceAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewAPIKeyResponses(keys))
+}
+
+// RotateKey godoc
+// @Summary Revoke an API key and issue a replacement with the same scopes (admin only)
+// @Tags Service Accounts
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 201 {object} dto.IssuedAPIKeyResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/api-keys/{id}/rotate [post]
+func (s *ServiceAccountController) RotateKey(c *gin.Context) {
+	keyID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	issued, err := s.svc.RotateKey(context.Background(), actorID, keyID)
+	if err != nil {
+		writeServiceAccountError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, issuedKeyResponse(issued))
+}
+
+// RevokeKey godoc
+// @Summary Revoke an API key (admin only)
+// @Tags Service Accounts
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/api-keys/{id} [delete]
+func (s *ServiceAccountController) RevokeKey(c *gin.Context) {
+	keyID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := s.svc.RevokeKey(context.Background(), keyID); err != nil {
+		writeServiceAccountError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func issuedKeyResponse(issued *services.IssuedAPIKey) dto.IssuedAPIKeyResponse {
+	return dto.IssuedAPIKeyResponse{
+		APIKeyResponse: dto.NewAPIKeyResponse(issued.Key),
+		Key:            issued.Secret,
+	}
+}
+
+func writeServiceAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidScope), errors.Is(err, services.ErrNotServiceAccount):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		writeUserError(c, err)
+	}
+}
@@ -0,0 +1,378 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/dto"
+	"property-backend/middleware"
+	"property-backend/models"
+	"property-backend/repositories"
+	"property-backend/services"
+)
+
+// UserController handles user administration endpoints
+type UserController struct {
+	svc services.UserService
+}
+
+// NewUserController creates a new UserController
+func NewUserController(svc services.UserService) *UserController {
+	return &UserController{svc: svc}
+}
+
+// ListRoles godoc
+// @Summary List all roles
+// @Tags Users
+// @Produce json
+// @Success 200 {array} models.RolesMaster
+// @Router /api/v1/roles [get]
+func (u *UserController) ListRoles(c *gin.Context) {
+	roles, err := u.svc.ListRoles(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetUserRoles godoc
+// @Summary List the roles held by a user
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {array} models.RolesMaster
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/{id}/roles [get]
+func (u *UserController) GetUserRoles(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	roles, err := u.svc.GetUserRoles(context.Background(), userID)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// GrantRole godoc
+// @Summary Grant a role to a user
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} models.RolesMaster
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/{id}/roles [post]
+func (u *UserController) GrantRole(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	var req struct {
+		RoleID uint   `json:"role_id" binding:"required_without=Role"`
+		Role   string `json:"role" binding:"required_without=RoleID"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := u.svc.GrantRole(context.Background(), userID, req.RoleID, req.Role)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, role)
+}
+
+// RevokeRole godoc
+// @Summary Revoke a role from a user
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role_id path int true "Role ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/users/{id}/roles/{role_id} [delete]
+func (u *UserController) RevokeRole(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	roleID, ok := uintParam(c, "role_id")
+	if !ok {
+		return
+	}
+	if err := u.svc.RevokeRole(context.Background(), userID, roleID); err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// InviteUser godoc
+// @Summary Create a user account with the given roles and email a set-password link (admin only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Success 201 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/users/invite [post]
+func (u *UserController) InviteUser(c *gin.Context) {
+	var req struct {
+		FirstName   string `json:"first_name" binding:"required"`
+		LastName    string `json:"last_name" binding:"required"`
+		Email       string `json:"email" binding:"required,email"`
+		PhoneNumber string `json:"phone_number"`
+		RoleIDs     []uint `json:"role_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := models.User{
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		Email:       req.Email,
+		PhoneNumber: req.PhoneNumber,
+	}
+	id, err := u.svc.InviteUser(context.Background(), &user, req.RoleIDs)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"id": id})
+}
+
+// UnlockUser godoc
+// @Summary Clear a sign-in lockout (admin only)
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/{id}/unlock [post]
+func (u *UserController) UnlockUser(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	if err := u.svc.UnlockUser(context.Background(), actorID, userID); err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListUsers godoc
+// @Summary List users (admin only)
+// @Tags Users
+// @Produce json
+// @Param limit query int false "Page size (max 100)"
+// @Param offset query int false "Rows to skip"
+// @Param cursor query string false "next_cursor from the previous page; replaces offset"
+// @Param search query string false "Match on name or email"
+// @Param active query bool false "Filter by active status"
+// @Success 200 {object} dto.PageResponse[dto.UserResponse]
+// @Router /api/v1/users [get]
+func (u *UserController) ListUsers(c *gin.Context) {
+	page, ok := pageParams(c)
+	if !ok {
+		return
+	}
+	filter := repositories.UserFilter{Search: c.Query("search")}
+	if v := c.Query("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid active"})
+			return
+		}
+		filter.Active = &active
+	}
+	users, info, err := u.svc.ListUsers(context.Background(), filter, page)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pageResponse(dto.NewUserResponses(users), page, info))
+}
+
+// GetUser godoc
+// @Summary Get a user (admin only)
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.UserResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/{id} [get]
+func (u *UserController) GetUser(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	user, err := u.svc.GetUser(context.Background(), userID)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewUserResponse(user))
+}
+
+// UpdateUser godoc
+// @Summary Update a user's profile (admin only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.UserResponse
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/{id} [put]
+func (u *UserController) UpdateUser(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	u.updateProfile(c, userID)
+}
+
+// DeactivateUser godoc
+// @Summary Deactivate a user; the row is kept but sign in is blocked (admin only)
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/users/{id} [delete]
+func (u *UserController) DeactivateUser(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	actorID, _ := middleware.CurrentUserID(c)
+	if err := u.svc.DeactivateUser(context.Background(), actorID, userID); err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ActivateUser godoc
+// @Summary Reactivate a deactivated user (admin only)
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/users/{id}/activate [post]
+func (u *UserController) ActivateUser(c *gin.Context) {
+	userID, ok := uintParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := u.svc.ActivateUser(context.Background(), userID); err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetMe godoc
+// @Summary Get the current user's profile
+// @Tags Users
+// @Produce json
+// @Success 200 {object} dto.UserResponse
+// @Router /api/v1/users/me [get]
+func (u *UserController) GetMe(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+	user, err := u.svc.GetUser(context.Background(), userID)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewUserResponse(user))
+}
+
+// UpdateMe godoc
+// @Summary Update the current user's name and phone number
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.UserResponse
+// @Router /api/v1/users/me [patch]
+func (u *UserController) UpdateMe(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+	u.updateProfile(c, userID)
+}
+
+// ChangeMyPassword godoc
+// @Summary Change the current user's password
+// @Description Signs out every session, including the current one; sign in again with the new password
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/users/me/password [post]
+func (u *UserController) ChangeMyPassword(c *gin.Context) {
+	var req struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required,min=8"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, _ := middleware.CurrentUserID(c)
+	if err := u.svc.ChangePassword(context.Background(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "current password is incorrect"})
+			return
+		}
+		writeUserError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (u *UserController) updateProfile(c *gin.Context, userID uint) {
+	var req struct {
+		FirstName   *string `json:"first_name" binding:"omitempty,min=1,max=100"`
+		LastName    *string `json:"last_name" binding:"omitempty,min=1,max=100"`
+		PhoneNumber *string `json:"phone_number" binding:"omitempty,max=15"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user, err := u.svc.UpdateUser(context.Background(), userID, services.UserUpdate{
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		PhoneNumber: req.PhoneNumber,
+	})
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.NewUserResponse(user))
+}
+
+// writeUserError maps user service errors to HTTP responses
+func writeUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound), errors.Is(err, services.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLastRole), errors.Is(err, services.ErrLastAdmin), errors.Is(err, services.ErrEmailTaken), errors.Is(err, services.ErrCannotDeactivateSelf):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"property-backend/models"
+)
+
+// APIKeyResponse describes an API key without its secret
+type APIKeyResponse struct {
+	APIKeyID   uint       `json:"api_key_id"`
+	UserID     uint       `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IssuedAPIKeyResponse is returned only when a key is created or rotated;
+// the plaintext key is never retrievable afterwards
+type IssuedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// NewAPIKeyResponse converts an api_keys row into its API representation
+func NewAPIKeyResponse(k *models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		APIKeyID:   k.APIKeyID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// NewAPIKeyResponses converts a slice of api_keys rows
+func NewAPIKeyResponses(keys []models.APIKey) []APIKeyResponse {
+	out := make([]APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		out = append(out, NewAPIKeyResponse(&keys[i]))
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"property-backend/models"
+)
+
+// DashboardSummary is the body of GET /dashboard/summary. Soft-deleted
+// properties, and the agreements and contracts attached to them, are left out.
+type DashboardSummary struct {
+	TotalProperties int64        `json:"total_properties"`
+	ByPropertyType  []GroupCount `json:"by_property_type"`
+	ByDistrict      []GroupCount `json:"by_district"`
+
+	// PortfolioValue sums the value of every property; UnvaluedProperties
+	// counts those without one, which the sum leaves out
+	PortfolioValue     models.Money `json:"portfolio_value"`
+	UnvaluedProperties int64        `json:"unvalued_properties"`
+
+	// Active agreements are those whose start and end dates straddle AsOf
+	MonthlyRentalIncome float64 `json:"monthly_rental_income"`
+	OccupiedProperties  int64   `json:"occupied_properties"`
+	// OccupancyRate is OccupiedProperties / TotalProperties, between 0 and 1
+	OccupancyRate float64 `json:"occupancy_rate"`
+
+	ExpiringAgreements ExpiryWindows `json:"expiring_agreements"`
+	ExpiringContracts  ExpiryWindows `json:"expiring_contracts"`
+	ExpiringAMCs       ExpiryWindows `json:"expiring_amcs"`
+
+	// TaxUnpaidProperties counts properties not marked tax paid, including
+	// those with no tax details at all
+	TaxUnpaidProperties int64 `json:"tax_unpaid_properties"`
+
+	AsOf time.Time `json:"as_of"`
+}
+
+// GroupCount is the number of properties in one group of a breakdown
+type GroupCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// ExpiryWindows counts records ending within 30, 60 and 90 days. The windows
+// are cumulative, so Within60Days includes Within30Days.
+type ExpiryWindows struct {
+	Within30Days int64 `json:"within_30_days"`
+	Within60Days int64 `json:"within_60_days"`
+	Within90Days int64 `json:"within_90_days"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// PageResponse is the envelope returned by every list endpoint. NextCursor,
+// when present, can be sent back as ?cursor= to fetch the following page.
+type PageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,191 @@
+package dto
+
+import "property-backend/models"
+
+// PropertyUpdate is the body of PUT and PATCH /properties/:id. PATCH only
+// touches the fields that are present; PUT replaces the whole record, so
+// absent fields and sections are cleared.
+type PropertyUpdate struct {
+	PropertyName     *string       `json:"property_name" binding:"omitempty,min=1,max=150"`
+	PropertyTypeID   *uint         `json:"property_type_id"`
+	PropertyTypeName *string       `json:"property_type_name"`
+	Value            *models.Money `json:"value"`
+	Income           *models.Money `json:"income"`
+	OriginalDeed     *string       `json:"original_deed"`
+	UnitCount        *int          `json:"unit_count"`
+
+	Address   *AddressUpdate         `json:"address"`
+	Land      *LandDetailsUpdate     `json:"land"`
+	Tax       *TaxDetailsUpdate      `json:"tax"`
+	Ownership *OwnershipUpdate       `json:"ownership"`
+	Building  *BuildingDetailsUpdate `json:"building"`
+	Media     *MediaUpdate           `json:"media"`
+}
+
+// AddressUpdate changes a property's address. The location names must be
+// given together; they are resolved (or created) down to the taluk.
+type AddressUpdate struct {
+	CountryName    *string `json:"country_name"`
+	StateName      *string `json:"state_name"`
+	DistrictName   *string `json:"district_name"`
+	TalukName      *string `json:"taluk_name"`
+	Hobli          *string `json:"hobli"`
+	Village        *string `json:"village"`
+	StreetAddress  *string `json:"street_address"`
+	Pincode        *string `json:"pincode" binding:"omitempty,max=10"`
+	LatCoordinate  *string `json:"lat_coordinate"`
+	LongCoordinate *string `json:"long_coordinate"`
+}
+
+// HasLocation reports whether any of the location names is present
+func (a *AddressUpdate) HasLocation() bool {
+	return a.CountryName != nil || a.StateName != nil || a.DistrictName != nil || a.TalukName != nil
+}
+
+// HasFullLocation reports whether all location names are present and non-empty
+func (a *AddressUpdate) HasFullLocation() bool {
+	for _, v := range []*string{a.CountryName, a.StateName, a.DistrictName, a.TalukName} {
+		if v == nil || *v == "" {
+			return false
+		}
+	}
+	return true
+}
+
+type LandDetailsUpdate struct {
+	Rtc        *string  `json:"rtc"`
+	Ec         *string  `json:"ec"`
+	SyNo       *string  `json:"sy_no"`
+	KhataNo    *string  `json:"khata_no"`
+	MrNo       *string  `json:"mr_no"`
+	Acre       *float64 `json:"acre"`
+	Gunte      *float64 `json:"gunte"`
+	Karab      *float64 `json:"karab"`
+	Converted  *string  `json:"converted"`
+	Purpose    *string  `json:"purpose"`
+	KhuskiTari *string  `json:"khuskitari"`
+}
+
+type TaxDetailsUpdate struct {
+	TaxPaid     *bool    `json:"tax_paid"`
+	ReceiptNo   *string  `json:"receipt_no"`
+	PrevAmount  *float64 `json:"tax_amount_paid_previous_year"`
+	CurrAmount  *float64 `json:"tax_amount_paid_current_year"`
+	ReceiptLink *string  `json:"receipt_photo_link"`
+}
+
+type OwnershipUpdate struct {
+	ReceivedFrom        *string `json:"received_from"`
+	AcquisitionType     *string `json:"acquisition_type"`
+	RegistrationDetails *string `json:"registration_details"`
+	Title               *string `json:"title"`
+	Incharge            *string `json:"incharge"`
+	PhoneNumber         *string `json:"phone_number" binding:"omitempty,max=15"`
+}
+
+type BuildingDetailsUpdate struct {
+	PlotSize           *float64 `json:"plot_size"`
+	BuiltUpArea        *float64 `json:"built_up_area"`
+	YearOfConstruction *string  `json:"year_of_construction"`
+	ApplicationNo      *string  `json:"application_no"`
+}
+
+type MediaUpdate struct {
+	ScannedDeedLink *string `json:"scanned_deed_link"`
+	PhotoLink       *string `json:"photo_link"`
+	Remarks         *string `json:"remarks"`
+}
+
+// PropertyCreate is the body of POST /properties. Sections use the same
+// field names as the GET response; an omitted section creates an empty
+// detail row so every property has all of them.
+type PropertyCreate struct {
+	PropertyName     string        `json:"property_name" binding:"required,max=150"`
+	PropertyTypeID   uint          `json:"property_type_id"`
+	PropertyTypeName string        `json:"property_type_name"`
+	Value            *models.Money `json:"value"`
+	Income           *models.Money `json:"income"`
+	OriginalDeed     string        `json:"original_deed"`
+	UnitCount        *int          `json:"unit_count"`
+
+	Address   AddressInput         `json:"address"`
+	Land      LandDetailsInput     `json:"land"`
+	Tax       TaxDetailsInput      `json:"tax"`
+	Ownership OwnershipInput       `json:"ownership"`
+	Building  BuildingDetailsInput `json:"building"`
+	Media     MediaInput           `json:"media"`
+}
+
+// AddressInput is the address section of a new property. The location
+// names are resolved (or created) down to the taluk.
+type AddressInput struct {
+	CountryName    string `json:"country_name"`
+	StateName      string `json:"state_name"`
+	DistrictName   string `json:"district_name"`
+	TalukName      string `json:"taluk_name"`
+	Hobli          string `json:"hobli"`
+	Village        string `json:"village"`
+	StreetAddress  string `json:"street_address"`
+	Pincode        string `json:"pincode" binding:"omitempty,max=10"`
+	LatCoordinate  string `json:"lat_coordinate"`
+	LongCoordinate string `json:"long_coordinate"`
+}
+
+// HasLocation reports whether any of the location names is set
+func (a AddressInput) HasLocation() bool {
+	return a.CountryName != "" || a.StateName != "" || a.DistrictName != "" || a.TalukName != ""
+}
+
+// HasFullLocation reports whether all location names are set
+func (a AddressInput) HasFullLocation() bool {
+	return a.CountryName != "" && a.StateName != "" && a.DistrictName != "" && a.TalukName != ""
+}
+
+// IsEmpty reports whether no address field is set at all
+func (a AddressInput) IsEmpty() bool {
+	return a == AddressInput{}
+}
+
+type LandDetailsInput struct {
+	Rtc        string   `json:"rtc"`
+	Ec         string   `json:"ec"`
+	SyNo       string   `json:"sy_no"`
+	KhataNo    string   `json:"khata_no"`
+	MrNo       string   `json:"mr_no"`
+	Acre       *float64 `json:"acre"`
+	Gunte      *float64 `json:"gunte"`
+	Karab      *float64 `json:"karab"`
+	Converted  string   `json:"converted"`
+	Purpose    string   `json:"purpose"`
+	KhuskiTari string   `json:"khuskitari"`
+}
+
+type TaxDetailsInput struct {
+	TaxPaid     bool    `json:"tax_paid"`
+	ReceiptNo   string  `json:"receipt_no"`
+	PrevAmount  float64 `json:"tax_amount_paid_previous_year"`
+	CurrAmount  float64 `json:"tax_amount_paid_current_year"`
+	ReceiptLink string  `json:"receipt_photo_link"`
+}
+
+type OwnershipInput struct {
+	ReceivedFrom        string `json:"received_from"`
+	AcquisitionType     string `json:"acquisition_type"`
+	RegistrationDetails string `json:"registration_details"`
+	Title               string `json:"title"`
+	Incharge            string `json:"incharge"`
+	PhoneNumber         string `json:"phone_number" binding:"omitempty,max=15"`
+}
+
+type BuildingDetailsInput struct {
+	PlotSize           *float64 `json:"plot_size"`
+	BuiltUpArea        *float64 `json:"built_up_area"`
+	YearOfConstruction string   `json:"year_of_construction"`
+	ApplicationNo      string   `json:"application_no"`
+}
+
+type MediaInput struct {
+	ScannedDeedLink string `json:"scanned_deed_link"`
+	PhotoLink       string `json:"photo_link"`
+	Remarks         string `json:"remarks"`
+}
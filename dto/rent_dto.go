@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"time"
+
+	"property-backend/models"
+)
+
+// Statuses of a billing period in a rent schedule
+const (
+	RentPaid          = "paid"
+	RentPartiallyPaid = "partially_paid"
+	RentOverdue       = "overdue"
+	RentDue           = "due"
+	RentUpcoming      = "upcoming"
+)
+
+// ScheduleLine is a billing period with the part of it payments have settled
+type ScheduleLine struct {
+	models.RentScheduleEntry
+	Paid        models.Money `json:"paid"`
+	Outstanding models.Money `json:"outstanding"`
+	Status      string       `json:"status"`
+}
+
+// RentLedger is the account of one agreement: rent charged as it fell due,
+// payments received, and the running balance after each
+type RentLedger struct {
+	AgreementID uint      `json:"agreement_id"`
+	PropertyID  uint      `json:"property_id"`
+	TenantName  string    `json:"tenant_name"`
+	UnitNo      string    `json:"unit_no"`
+	AsOf        time.Time `json:"as_of"`
+
+	TotalDue  models.Money `json:"total_due"`
+	TotalPaid models.Money `json:"total_paid"`
+	// Balance is owed by the tenant when positive and paid in advance when negative
+	Balance models.Money `json:"balance"`
+	// Arrears is the rent due before today that is still unpaid
+	Arrears models.Money `json:"arrears"`
+
+	Lines []LedgerLine `json:"lines"`
+}
+
+// LedgerLine is one charge (debit) or payment (credit) in a RentLedger
+type LedgerLine struct {
+	Date        time.Time    `json:"date"`
+	Description string       `json:"description"`
+	Debit       models.Money `json:"debit"`
+	Credit      models.Money `json:"credit"`
+	Balance     models.Money `json:"balance"`
+	ScheduleID  uint         `json:"schedule_id,omitempty"`
+	PaymentID   uint         `json:"payment_id,omitempty"`
+}
+
+// OverdueRent is an agreement with rent unpaid past its due date
+type OverdueRent struct {
+	AgreementID    uint         `json:"agreement_id"`
+	PropertyID     uint         `json:"property_id"`
+	PropertyName   string       `json:"property_name"`
+	TenantName     string       `json:"tenant_name"`
+	ContactNo      string       `json:"contact_no"`
+	UnitNo         string       `json:"unit_no"`
+	OverdueAmount  models.Money `json:"overdue_amount"`
+	OverduePeriods int64        `json:"overdue_periods"`
+	OldestDueDate  time.Time    `json:"oldest_due_date"`
+	DaysOverdue    int          `json:"days_overdue"`
+}
+
+// EffectiveRent is the monthly rent of an agreement on a given date after
+// the escalations that have taken effect by then
+type EffectiveRent struct {
+	AgreementID        uint         `json:"agreement_id"`
+	Date               time.Time    `json:"date"`
+	BaseRent           models.Money `json:"base_rent"`
+	MonthlyRent        models.Money `json:"monthly_rent"`
+	EscalationsApplied int          `json:"escalations_applied"`
+	// NextEscalationDate and NextMonthlyRent are omitted when the rent does
+	// not rise again before the agreement ends
+	NextEscalationDate *time.Time    `json:"next_escalation_date,omitempty"`
+	NextMonthlyRent    *models.Money `json:"next_monthly_rent,omitempty"`
+}
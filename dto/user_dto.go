@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"property-backend/models"
+)
+
+// UserResponse is the public representation of a user returned by the API
+type UserResponse struct {
+	UserID           uint       `json:"user_id"`
+	FirstName        string     `json:"first_name"`
+	LastName         string     `json:"last_name"`
+	Email            string     `json:"email"`
+	PhoneNumber      string     `json:"phone_number"`
+	RoleID           uint       `json:"role_id"`
+	EmailVerified    bool       `json:"email_verified"`
+	IsActive         bool       `json:"is_active"`
+	IsServiceAccount bool       `json:"is_service_account"`
+	TOTPEnabled      bool       `json:"totp_enabled"`
+	LockedUntil      *time.Time `json:"locked_until,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// NewUserResponse converts a user row into its API representation
+func NewUserResponse(u *models.User) UserResponse {
+	return UserResponse{
+		UserID:           u.UserID,
+		FirstName:        u.FirstName,
+		LastName:         u.LastName,
+		Email:            u.Email,
+		PhoneNumber:      u.PhoneNumber,
+		RoleID:           u.RoleID,
+		EmailVerified:    u.EmailVerified,
+		IsActive:         u.IsActive,
+		IsServiceAccount: u.IsServiceAccount,
+		TOTPEnabled:      u.TOTPEnabled,
+		LockedUntil:      u.LockedUntil,
+		CreatedAt:        u.CreatedAt,
+	}
+}
+
+// NewUserResponses converts a slice of user rows
+func NewUserResponses(users []models.User) []UserResponse {
+	out := make([]UserResponse, 0, len(users))
+	for i := range users {
+		out = append(out, NewUserResponse(&users[i]))
+	}
+	return out
+}
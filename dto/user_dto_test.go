@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"property-backend/models"
+)
+
+// TestUserPayloadsOmitSecrets covers every shape a user is serialized in.
+// Sign in, the 2FA steps and refresh embed UserResponse in their token
+// payload, and the user list wraps it in a page.
+func TestUserPayloadsOmitSecrets(t *testing.T) {
+	locked := time.Now().Add(time.Hour)
+	// every credential and sign-in bookkeeping field is set, so a leak shows
+	// up as either the JSON key or the value
+	u := models.User{
+		UserID:           7,
+		FirstName:        "Asha",
+		LastName:         "Rao",
+		Email:            "asha@example.com",
+		HashedPassword:   "$2a$10$secret-password-hash",
+		FailedLoginCount: 4,
+		LockedUntil:      &locked,
+		TOTPSecret:       "JBSWY3DPEHPK3PXP",
+		TOTPEnabled:      true,
+		TOTPLastStep:     123456,
+		TokenVersion:     3,
+	}
+	leaks := []string{
+		"hashed_password", "$2a$10$secret-password-hash",
+		"totp_secret", "JBSWY3DPEHPK3PXP",
+		"failed_login_count", "totp_last_step", "token_version",
+	}
+
+	for _, tc := range []struct {
+		name    string
+		payload interface{}
+	}{
+		{"model", u},
+		{"model pointer", &u},
+		{"response", NewUserResponse(&u)},
+		{"response list", NewUserResponses([]models.User{u, u})},
+		{"page", PageResponse[UserResponse]{Items: NewUserResponses([]models.User{u}), Total: 1}},
+	} {
+		b, err := json.Marshal(tc.payload)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", tc.name, err)
+		}
+		out := string(b)
+		for _, leak := range leaks {
+			if strings.Contains(out, leak) {
+				t.Errorf("%s JSON contains %q: %s", tc.name, leak, out)
+			}
+		}
+		if !strings.Contains(out, `"email":"asha@example.com"`) {
+			t.Errorf("%s JSON is missing the user: %s", tc.name, out)
+		}
+	}
+}
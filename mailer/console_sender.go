@@ -0,0 +1,19 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// ConsoleSender writes messages to the standard logger instead of sending them
+type ConsoleSender struct{}
+
+// NewConsoleSender constructs a ConsoleSender
+func NewConsoleSender() *ConsoleSender {
+	return &ConsoleSender{}
+}
+
+func (s *ConsoleSender) Send(ctx context.Context, msg Message) error {
+	log.Printf("📧 mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileSender writes every message to its own .eml file in a directory
+type FileSender struct {
+	dir string
+}
+
+// NewFileSender constructs a FileSender that writes into dir
+func NewFileSender(dir string) *FileSender {
+	return &FileSender{dir: dir}
+}
+
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s.eml",
+		time.Now().Format("20060102T150405.000000000"),
+		strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To),
+	)
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		msg.To, msg.Subject, time.Now().Format(time.RFC1123Z), msg.Body)
+	return os.WriteFile(filepath.Join(s.dir, name), []byte(content), 0644)
+}
@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+	"os"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSenderFromEnv picks a Sender based on MAIL_DRIVER:
+//   - "file": writes each message to MAIL_FILE_DIR (default logs/mail)
+//   - anything else: logs messages to the application log
+func NewSenderFromEnv() Sender {
+	switch os.Getenv("MAIL_DRIVER") {
+	case "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "logs/mail"
+		}
+		return NewFileSender(dir)
+	default:
+		return NewConsoleSender()
+	}
+}
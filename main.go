@@ -13,6 +13,8 @@ import (
 	"property-backend/config"
 	"property-backend/controllers"
 	_ "property-backend/docs"
+	"property-backend/mailer"
+	"property-backend/middleware"
 	"property-backend/repositories"
 	"property-backend/routes"
 	"property-backend/services"
@@ -30,6 +32,14 @@ func main() {
 
 	// setup gin
 	r := gin.Default()
+	// only listed proxies may set the client IP through X-Forwarded-For
+	proxies, err := config.TrustedProxies()
+	if err != nil {
+		log.Fatal("invalid TRUSTED_PROXIES: ", err)
+	}
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		log.Fatal("invalid TRUSTED_PROXIES: ", err)
+	}
     db := config.DB
 	
 
@@ -42,16 +52,33 @@ func main() {
 	// Construct repositories (db may be nil)
 	authRepo := repositories.NewAuthRepository(db)
 	propertyRepo := repositories.NewPropertyRepository(db)
+	valuationRepo := repositories.NewValuationRepository(db)
 	agreementRepo := repositories.NewAgreementRepository(db)
 	assetRepo := repositories.NewAssetRepository(db)
 	contractRepo := repositories.NewContractRepository(db)
+	roleRepo := repositories.NewRoleRepository(db)
+	userTokenRepo := repositories.NewUserTokenRepository(db)
+	securityRepo := repositories.NewSecurityRepository(db)
+	apiKeyRepo := repositories.NewAPIKeyRepository(db)
+	mfaRepo := repositories.NewMFARepository(db)
+	dashboardRepo := repositories.NewDashboardRepository(db)
+	rentRepo := repositories.NewRentRepository(db)
+
+	// Outgoing mail (console or file driver for local development)
+	mailSender := mailer.NewSenderFromEnv()
 
 	// Construct services
-	authSvc := services.NewAuthService(authRepo)
-	propertySvc := services.NewPropertyService(propertyRepo)
+	mfaSvc := services.NewMFAService(authRepo, roleRepo, mfaRepo, securityRepo)
+	authSvc := services.NewAuthService(authRepo, roleRepo, userTokenRepo, securityRepo, apiKeyRepo, mfaSvc, mailSender)
+	authzSvc := services.NewAuthorizationService(authRepo)
+	propertySvc := services.NewPropertyService(propertyRepo, valuationRepo)
 	agreementSvc := services.NewAgreementService(agreementRepo)
 	assetSvc := services.NewAssetService(assetRepo)
 	contractSvc := services.NewContractService(contractRepo)
+	userSvc := services.NewUserService(authRepo, roleRepo, userTokenRepo, securityRepo, mailSender)
+	serviceAccountSvc := services.NewServiceAccountService(authRepo, roleRepo, apiKeyRepo)
+	dashboardSvc := services.NewDashboardService(dashboardRepo)
+	rentSvc := services.NewRentService(rentRepo)
 
 	// Instantiate controllers with services
 	authController := controllers.NewAuthController(authSvc)
@@ -59,15 +86,29 @@ func main() {
 	agreementController := controllers.NewAgreementController(agreementSvc)
 	assetController := controllers.NewAssetController(assetSvc)
 	contractController := controllers.NewContractController(contractSvc)
+	userController := controllers.NewUserController(userSvc)
+	serviceAccountController := controllers.NewServiceAccountController(serviceAccountSvc)
+	mfaController := controllers.NewMFAController(mfaSvc)
+	dashboardController := controllers.NewDashboardController(dashboardSvc)
+	rentController := controllers.NewRentController(rentSvc)
+
+	// Auth middleware validates access tokens and permissions on protected routes
+	authMiddleware := middleware.NewAuthMiddleware(authSvc, authzSvc)
 
 	// Register routes under /api/v1
 	routes.RegisterRoutes(
 		apiV1,
+		authMiddleware,
 		authController,
 		propertyController,
 		agreementController,
 		assetController,
 		contractController,
+		userController,
+		serviceAccountController,
+		mfaController,
+		dashboardController,
+		rentController,
 	)
 
 	
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"property-backend/services"
+)
+
+func TestRequireUserSessionRejectsAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := NewAuthMiddleware(nil, nil)
+	for _, tc := range []struct {
+		name   string
+		apiKey bool
+		want   int
+	}{
+		{"bearer token", false, http.StatusNoContent},
+		{"api key", true, http.StatusForbidden},
+	} {
+		r := gin.New()
+		r.POST("/users/me/password", func(c *gin.Context) {
+			c.Set(ContextUserIDKey, uint(7))
+			if tc.apiKey {
+				c.Set(ContextAPIKeyScopesKey, []services.Permission{services.PermPropertiesWrite})
+			}
+		}, m.RequireUserSession(), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/me/password", nil))
+		if w.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
+
+func TestRequireLimitsAPIKeysToTheirScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// API-key requests never consult the role-based authorization service
+	m := NewAuthMiddleware(nil, nil)
+	for _, tc := range []struct {
+		name   string
+		scopes []services.Permission
+		perm   services.Permission
+		want   int
+	}{
+		{"scope granted", []services.Permission{services.PermRentWrite}, services.PermRentWrite, http.StatusNoContent},
+		{"one of several scopes", []services.Permission{services.PermPropertiesRead, services.PermRentWrite}, services.PermRentWrite, http.StatusNoContent},
+		{"read scope for a write route", []services.Permission{services.PermPropertiesRead}, services.PermPropertiesWrite, http.StatusForbidden},
+		{"no scopes", []services.Permission{}, services.PermPropertiesRead, http.StatusForbidden},
+		{"user administration", []services.Permission{services.PermPropertiesWrite}, services.PermUsersManage, http.StatusForbidden},
+	} {
+		r := gin.New()
+		r.GET("/resource", func(c *gin.Context) {
+			c.Set(ContextUserIDKey, uint(7))
+			c.Set(ContextAPIKeyScopesKey, tc.scopes)
+		}, m.Require(tc.perm), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resource", nil))
+		if w.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, w.Code, tc.want)
+		}
+	}
+}
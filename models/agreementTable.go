@@ -15,7 +15,20 @@ type Agreement struct {
 	Deposit     float64   `gorm:"column:deposit" json:"deposit"`
 	StartDate   time.Time `gorm:"column:start_date;not null" json:"start_date"`
 	EndDate     time.Time `gorm:"column:end_date;not null" json:"end_date"`
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	// Rent is monthly; RentFrequency sets how often it is billed
+	RentFrequency string `gorm:"column:rent_frequency;type:varchar(20);not null;default:'monthly'" json:"rent_frequency"`
+	// UnitNo is the let unit of the property; empty means the whole property
+	UnitNo string `gorm:"column:unit_no;type:varchar(50);not null;default:''" json:"unit_no"`
+	// NoticeDate is when notice to vacate was given, by either party
+	NoticeDate *time.Time `gorm:"column:notice_date;type:date" json:"notice_date"`
+	// Escalation raises the rent every EscalationIntervalMonths from the start
+	// date, by EscalationValue percent or a fixed EscalationValue amount,
+	// never above EscalationCap (a monthly rent) when that is set
+	EscalationType           string    `gorm:"column:escalation_type;type:varchar(20);not null;default:''" json:"escalation_type"`
+	EscalationValue          float64   `gorm:"column:escalation_value;not null;default:0" json:"escalation_value"`
+	EscalationIntervalMonths int       `gorm:"column:escalation_interval_months;not null;default:0" json:"escalation_interval_months"`
+	EscalationCap            *float64  `gorm:"column:escalation_cap" json:"escalation_cap"`
+	CreatedAt                time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 
 	/* relation */
 	Property Property `gorm:"foreignKey:PropertyID;references:PropertyID" json:"property"`
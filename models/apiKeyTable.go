@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+/* =========================
+   api_keys
+   keys for service accounts; only the SHA-256 hash of a key is stored
+========================= */
+
+type APIKey struct {
+	APIKeyID   uint       `gorm:"column:api_key_id;primaryKey;autoIncrement" json:"api_key_id"`
+	UserID     uint       `gorm:"column:user_id;not null;index" json:"user_id"`
+	Name       string     `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"column:prefix;type:varchar(20);not null" json:"prefix"`
+	KeyHash    string     `gorm:"column:key_hash;type:varchar(64);not null;unique" json:"-"`
+	Scopes     string     `gorm:"column:scopes;type:text;not null" json:"-"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedBy  uint       `gorm:"column:created_by" json:"created_by"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID;references:UserID" json:"-"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList returns the permissions stored in Scopes
+func (k APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
@@ -27,7 +27,7 @@ type Asset struct {
 	AssetTypeID uint `gorm:"column:asset_type_id;not null" json:"asset_type_id"`
 
 	AssetLocation    string `gorm:"column:location;type:varchar(150)" json:"asset_location"`
-	AssetCost        string `gorm:"column:cost;type:varchar(50)" json:"asset_cost"`
+	AssetCost        *Money `gorm:"column:cost;type:numeric(15,2)" json:"asset_cost"`
 	AssetAMCProvider string `gorm:"column:amc_provider;type:varchar(150)" json:"asset_amc_provider"`
 	AssetStartDate   string `gorm:"column:start_date;type:varchar(20)" json:"asset_start_date"`
 	AssetEndDate     string `gorm:"column:end_date;type:varchar(20)" json:"asset_end_date"`
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+/* =========================
+   data_cleanup_report
+========================= */
+
+// DataCleanupReport records a value a schema migration could not convert.
+// The column is left NULL and the original text is kept here for review.
+type DataCleanupReport struct {
+	ReportID      uint      `gorm:"column:report_id;primaryKey;autoIncrement" json:"report_id"`
+	SourceTable   string    `gorm:"column:source_table;type:varchar(100);not null;index:idx_cleanup_row" json:"source_table"`
+	SourceColumn  string    `gorm:"column:source_column;type:varchar(100);not null" json:"source_column"`
+	RowID         uint      `gorm:"column:row_id;not null;index:idx_cleanup_row" json:"row_id"`
+	OriginalValue string    `gorm:"column:original_value;type:text" json:"original_value"`
+	Reason        string    `gorm:"column:reason;type:varchar(255)" json:"reason"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (DataCleanupReport) TableName() string {
+	return "data_cleanup_report"
+}
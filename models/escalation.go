@@ -0,0 +1,70 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"property-backend/utils"
+)
+
+// Escalation types; an empty Agreement.EscalationType means the rent is fixed
+const (
+	EscalationPercentage = "percentage"
+	EscalationFixed      = "fixed"
+)
+
+// EscalationTypes lists the accepted values of Agreement.EscalationType
+var EscalationTypes = []string{EscalationPercentage, EscalationFixed}
+
+// Escalates reports whether the agreement has an escalation clause
+func (a *Agreement) Escalates() bool {
+	return a.EscalationType != "" && a.EscalationValue > 0 && a.EscalationIntervalMonths > 0
+}
+
+// EscalationDate is the day the n-th escalation takes effect; the 0th is the
+// start date. Escalations are counted from the start date rather than from
+// each other, so 31 Jan stays on the 31st in long months.
+func (a *Agreement) EscalationDate(n int) time.Time {
+	return utils.AddMonths(utils.DateOf(a.StartDate), n*a.EscalationIntervalMonths)
+}
+
+// EscalationsBy counts the escalations that have taken effect on or before t
+func (a *Agreement) EscalationsBy(t time.Time) int {
+	if !a.Escalates() {
+		return 0
+	}
+	day, n := utils.DateOf(t), 0
+	for !a.EscalationDate(n + 1).After(day) {
+		n++
+	}
+	return n
+}
+
+// RentAfter is the monthly rent after n escalations. Percentage escalations
+// compound, each rounded to the paisa; the cap never lowers the agreed rent.
+func (a *Agreement) RentAfter(n int) float64 {
+	rent := a.Rent
+	if !a.Escalates() {
+		return rent
+	}
+	for i := 0; i < n; i++ {
+		if a.EscalationCap != nil && rent >= *a.EscalationCap {
+			break
+		}
+		switch a.EscalationType {
+		case EscalationPercentage:
+			rent = math.Round(rent*(100+a.EscalationValue)) / 100
+		case EscalationFixed:
+			rent = math.Round((rent+a.EscalationValue)*100) / 100
+		}
+		if a.EscalationCap != nil && rent > *a.EscalationCap {
+			rent = *a.EscalationCap
+		}
+	}
+	return rent
+}
+
+// RentOn is the monthly rent in effect on t
+func (a *Agreement) RentOn(t time.Time) float64 {
+	return a.RentAfter(a.EscalationsBy(t))
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Money
+		ok   bool
+	}{
+		{"1500", 150000, true},
+		{"1,500.5", 150050, true},
+		{"1500.05", 150005, true},
+		{" -20.75 ", -2075, true},
+		{"0.1", 10, true},
+		{"0", 0, true},
+		{"1500.", 0, false},
+		{".5", 0, false},
+		{"12.345", 0, false},
+		{"1e3", 0, false},
+		{"+5", 0, false},
+		{"--5", 0, false},
+		{"12a", 0, false},
+		{"", 0, false},
+		{"NaN", 0, false},
+		{"99999999999999999999", 0, false},
+	} {
+		got, err := ParseMoney(tc.in)
+		if tc.ok && (err != nil || got != tc.want) {
+			t.Errorf("ParseMoney(%q) = %s, %v; want %s", tc.in, got, err, tc.want)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("ParseMoney(%q) = %s, %v; want ErrInvalidMoney", tc.in, got, err)
+		}
+	}
+}
+
+func TestMoneyFromFloatRounds(t *testing.T) {
+	for _, tc := range []struct {
+		in   float64
+		want Money
+	}{
+		{1500, 150000},
+		{0.1 + 0.2, 30},
+		{10.125, 1013},
+		{-10.125, -1013},
+		{1234.5678, 123457},
+		{0.004, 0},
+	} {
+		if got := MoneyFromFloat(tc.in); got != tc.want {
+			t.Errorf("MoneyFromFloat(%v) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	for _, tc := range []struct {
+		in   Money
+		want string
+	}{
+		{150050, "1500.50"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{-2075, "-20.75"},
+		{0, "0.00"},
+	} {
+		if got := tc.in.String(); got != tc.want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(tc.in), got, tc.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Money
+		ok   bool
+	}{
+		{`1500.5`, 150050, true},
+		{`-20`, -2000, true},
+		{`null`, 99, true},
+		{`"1500"`, 0, false},
+		{`1e3`, 0, false},
+		{`1E3`, 0, false},
+		{`12.345`, 0, false},
+		{`true`, 0, false},
+	} {
+		m := Money(99)
+		err := m.UnmarshalJSON([]byte(tc.in))
+		if tc.ok && (err != nil || m != tc.want) {
+			t.Errorf("UnmarshalJSON(%s) = %s, %v; want %s", tc.in, m, err, tc.want)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("UnmarshalJSON(%s): %v, want ErrInvalidMoney", tc.in, err)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	for _, tc := range []struct {
+		in   interface{}
+		want Money
+		ok   bool
+	}{
+		{[]byte("1500.50"), 150050, true},
+		{"20.75", 2075, true},
+		{int64(15), 1500, true},
+		{12.5, 1250, true},
+		{nil, 0, true},
+		{"abc", 0, false},
+		{true, 0, false},
+	} {
+		m := Money(99)
+		err := m.Scan(tc.in)
+		if tc.ok && (err != nil || m != tc.want) {
+			t.Errorf("Scan(%#v) = %s, %v; want %s", tc.in, m, err, tc.want)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("Scan(%#v) = %s, want an error", tc.in, m)
+		}
+	}
+}
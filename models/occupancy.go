@@ -0,0 +1,17 @@
+package models
+
+// Occupancy statuses of a property, computed from the agreements running today
+const (
+	// OccupancyVacant means no agreement covers the property
+	OccupancyVacant = "vacant"
+	// OccupancyLet means the whole property, or every one of its units, is let
+	OccupancyLet = "let"
+	// OccupancyPartiallyLet means some but not all of the property's units are let
+	OccupancyPartiallyLet = "partially_let"
+	// OccupancyNoticePeriod means the property is let but every running
+	// agreement has been given notice
+	OccupancyNoticePeriod = "notice_period"
+)
+
+// OccupancyStatuses lists every occupancy status
+var OccupancyStatuses = []string{OccupancyVacant, OccupancyLet, OccupancyPartiallyLet, OccupancyNoticePeriod}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 /* =========================
    country_master
@@ -74,10 +78,15 @@ type Property struct {
 	PropertyTypeID uint      `gorm:"column:property_type_id;not null" json:"property_type_id"`
 	AddressID      uint      `gorm:"column:address_id;not null" json:"address_id"`
 	UserID         uint      `gorm:"column:user_id;not null" json:"user_id"`
-	Value          string    `gorm:"column:value;type:varchar(50)" json:"value"`
-	Income         string    `gorm:"column:income;type:varchar(50)" json:"income"`
+	Value          *Money    `gorm:"column:value;type:numeric(15,2)" json:"value"`
+	Income         *Money    `gorm:"column:income;type:numeric(15,2)" json:"income"`
 	OriginalDeed   string    `gorm:"column:original_deed;type:varchar(10)" json:"original_deed"`
+	// UnitCount is the number of separately let units; empty means the
+	// property is let as a whole
+	UnitCount      *int      `gorm:"column:unit_count" json:"unit_count"`
 	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	// DeletedAt makes deletes soft: gorm excludes these rows from every query
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
 
 	Address      Address            `gorm:"foreignKey:AddressID;references:AddressID;constraint:OnDelete:RESTRICT"`
 	PropertyType PropertyTypeMaster `gorm:"foreignKey:PropertyTypeID;references:PropertyTypeID"`
@@ -87,6 +96,21 @@ type Property struct {
 	Ownership       *PropertyOwnershipDetails `gorm:"foreignKey:PropertyID"`
 	BuildingDetails *PropertyBuildingDetails  `gorm:"foreignKey:PropertyID"`
 	Media           *PropertyMedia            `gorm:"foreignKey:PropertyID"`
+
+	// OccupancyStatus is computed when a property is read, never stored
+	OccupancyStatus string `gorm:"column:occupancy_status;->;-:migration" json:"occupancy_status,omitempty"`
+	// Area is filled in for responses in the unit the client asked for
+	Area *AreaSummary `gorm:"-" json:"area,omitempty"`
+}
+
+// AreaSummary reports a property's land and building areas in one unit
+type AreaSummary struct {
+	Unit        string   `json:"unit"`
+	Total       *float64 `json:"total"`
+	Karab       *float64 `json:"karab"`
+	Usable      *float64 `json:"usable"`
+	PlotSize    *float64 `json:"plot_size"`
+	BuiltUpArea *float64 `json:"built_up_area"`
 }
 
 func (Property) TableName() string {
@@ -119,13 +143,19 @@ type PropertyLandDetails struct {
 	SyNo      string `gorm:"column:sy_no;type:varchar(100)" json:"sy_no"`
 	KhataNo   string `gorm:"column:khata_no;type:varchar(100)" json:"khata_no"`
 	MrNo      string `gorm:"column:mr_no;type:varchar(100)" json:"mr_no"`
-	Acre      string `gorm:"column:acre;type:varchar(50)" json:"acre"`
-	Gunte     string `gorm:"column:gunte;type:varchar(50)" json:"gunte"`
-	Karab     string `gorm:"column:karab;type:varchar(50)" json:"karab"`
+	// The extent is Acre acres plus Gunte gunta (40 gunta = 1 acre); Karab
+	// is the unusable portion, in gunta
+	Acre      *float64 `gorm:"column:acre;type:numeric(12,4)" json:"acre"`
+	Gunte     *float64 `gorm:"column:gunte;type:numeric(12,4)" json:"gunte"`
+	Karab     *float64 `gorm:"column:karab;type:numeric(12,4)" json:"karab"`
 	Converted string `gorm:"column:converted;type:varchar(10)" json:"converted"`
 	Purpose   string `gorm:"column:purpose;type:varchar(100)" json:"purpose"`
 	KhuskiTari string `gorm:"column:khuski_tari;type:varchar(50)" json:"khuskitari"`
 
+	// Canonical areas in square metres, recomputed whenever the extent changes
+	TotalAreaSqm  *float64 `gorm:"column:total_area_sqm;type:numeric(14,2)" json:"total_area_sqm"`
+	UsableAreaSqm *float64 `gorm:"column:usable_area_sqm;type:numeric(14,2)" json:"usable_area_sqm"`
+
 	Property *Property `gorm:"foreignKey:PropertyID;references:PropertyID;constraint:OnDelete:CASCADE"`
 }
 
@@ -172,8 +202,9 @@ type PropertyBuildingDetails struct {
 	BuildingDetailsID uint `gorm:"column:building_details_id;primaryKey;autoIncrement" json:"building_details_id"`
 	PropertyID        uint `gorm:"column:property_id;not null;unique" json:"property_id"`
 
-	PlotSize           string `gorm:"column:plot_size;type:varchar(50)" json:"plot_size"`
-	BuiltUpArea        string `gorm:"column:built_up_area;type:varchar(50)" json:"built_up_area"`
+	// PlotSize and BuiltUpArea are in square feet
+	PlotSize           *float64 `gorm:"column:plot_size;type:numeric(14,2)" json:"plot_size"`
+	BuiltUpArea        *float64 `gorm:"column:built_up_area;type:numeric(14,2)" json:"built_up_area"`
 	YearOfConstruction string `gorm:"column:year_of_construction;type:varchar(10)" json:"year_of_construction"`
 	ApplicationNo      string `gorm:"column:application_no;type:varchar(100)" json:"application_no"`
 
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+/* =========================
+   mfa_recovery_codes
+========================= */
+
+// RecoveryCode is a single-use fallback for a lost authenticator; only the
+// SHA-256 hash of the code is stored
+type RecoveryCode struct {
+	RecoveryCodeID uint       `gorm:"column:recovery_code_id;primaryKey;autoIncrement" json:"recovery_code_id"`
+	UserID         uint       `gorm:"column:user_id;not null;index" json:"user_id"`
+	CodeHash       string     `gorm:"column:code_hash;type:varchar(64);not null" json:"-"`
+	UsedAt         *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (RecoveryCode) TableName() string {
+	return "mfa_recovery_codes"
+}
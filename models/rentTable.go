@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Rent frequencies; Agreement.Rent is always the monthly rent
+const (
+	RentMonthly   = "monthly"
+	RentQuarterly = "quarterly"
+)
+
+// RentFrequencyMonths is the length of one billing period of each frequency
+var RentFrequencyMonths = map[string]int{
+	RentMonthly:   1,
+	RentQuarterly: 3,
+}
+
+// Payment modes
+const (
+	PaymentCash         = "cash"
+	PaymentCheque       = "cheque"
+	PaymentBankTransfer = "bank_transfer"
+	PaymentUPI          = "upi"
+)
+
+// PaymentModes lists the accepted values of RentPayment.Mode
+var PaymentModes = []string{PaymentCash, PaymentCheque, PaymentBankTransfer, PaymentUPI}
+
+/* =========================
+   rent_schedule
+========================= */
+
+// RentScheduleEntry is one billing period of an agreement. Rent is due in
+// advance on the first day of the period; a short final period is charged
+// pro rata by days.
+type RentScheduleEntry struct {
+	ScheduleID  uint      `gorm:"column:schedule_id;primaryKey;autoIncrement" json:"schedule_id"`
+	AgreementID uint      `gorm:"column:agreement_id;not null;uniqueIndex:idx_schedule_agreement_period,priority:1" json:"agreement_id"`
+	PeriodStart time.Time `gorm:"column:period_start;type:date;not null;uniqueIndex:idx_schedule_agreement_period,priority:2" json:"period_start"`
+	PeriodEnd   time.Time `gorm:"column:period_end;type:date;not null" json:"period_end"`
+	DueDate     time.Time `gorm:"column:due_date;type:date;not null;index" json:"due_date"`
+	Amount      Money     `gorm:"column:amount;type:numeric(15,2);not null" json:"amount"`
+}
+
+func (RentScheduleEntry) TableName() string {
+	return "rent_schedule"
+}
+
+/* =========================
+   rent_payments
+========================= */
+
+// RentPayment is rent received against an agreement. Payments are not tied
+// to a period; they settle the oldest rent due first.
+type RentPayment struct {
+	PaymentID   uint      `gorm:"column:payment_id;primaryKey;autoIncrement" json:"payment_id"`
+	AgreementID uint      `gorm:"column:agreement_id;not null;index" json:"agreement_id"`
+	Amount      Money     `gorm:"column:amount;type:numeric(15,2);not null" json:"amount"`
+	PaidOn      time.Time `gorm:"column:paid_on;type:date;not null" json:"paid_on"`
+	Mode        string    `gorm:"column:mode;type:varchar(30)" json:"mode"`
+	Reference   string    `gorm:"column:reference;type:varchar(100)" json:"reference"`
+	Notes       string    `gorm:"column:notes;type:text" json:"notes"`
+	CreatedBy   uint      `gorm:"column:created_by" json:"created_by"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (RentPayment) TableName() string {
+	return "rent_payments"
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Audit event types
+const (
+	AuditAccountLocked       = "account_locked"
+	AuditAccountUnlocked     = "account_unlocked"
+	AuditIPThrottled         = "ip_throttled"
+	AuditMFAEnabled          = "mfa_enabled"
+	AuditMFADisabled         = "mfa_disabled"
+	AuditMFAReset            = "mfa_reset"
+	AuditMFARecoveryCodeUsed = "mfa_recovery_code_used"
+	AuditMFAPolicyChanged    = "mfa_policy_changed"
+)
+
+/* =========================
+   login_attempts
+========================= */
+
+type LoginAttempt struct {
+	LoginAttemptID uint      `gorm:"column:login_attempt_id;primaryKey;autoIncrement" json:"login_attempt_id"`
+	Email          string    `gorm:"column:email;type:varchar(150);index" json:"email"`
+	IPAddress      string    `gorm:"column:ip_address;type:varchar(45);index" json:"ip_address"`
+	Success        bool      `gorm:"column:success;not null" json:"success"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime;index" json:"created_at"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+/* =========================
+   audit_logs
+========================= */
+
+type AuditLog struct {
+	AuditLogID  uint      `gorm:"column:audit_log_id;primaryKey;autoIncrement" json:"audit_log_id"`
+	EventType   string    `gorm:"column:event_type;type:varchar(50);not null;index" json:"event_type"`
+	UserID      *uint     `gorm:"column:user_id;index" json:"user_id"`
+	ActorUserID *uint     `gorm:"column:actor_user_id" json:"actor_user_id"`
+	Email       string    `gorm:"column:email;type:varchar(150)" json:"email"`
+	IPAddress   string    `gorm:"column:ip_address;type:varchar(45)" json:"ip_address"`
+	Details     string    `gorm:"column:details;type:text" json:"details"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -10,15 +10,39 @@ import (
    user
 ========================= */
 
+// User.RoleID is the user's primary role. The full set of roles a user holds
+// lives in user_roles, which always contains the primary role as well.
+//
+// TokenVersion is stamped into every token issued to the user; changing or
+// resetting the password bumps it, which revokes all earlier sessions.
+//
+// TOTPSecret is set as soon as enrollment starts; two-factor sign in is only
+// enforced once TOTPEnabled is true. TOTPLastStep is the last accepted TOTP
+// time step and stops a code from being used twice.
+//
+// Credential and sign-in bookkeeping fields are tagged json:"-" so they can
+// never be serialized, even when a User is preloaded inside another model.
+// API responses should use dto.UserResponse instead.
 type User struct {
-	UserID         uint      `gorm:"column:user_id;primaryKey;autoIncrement" json:"user_id"`
-	FirstName      string    `gorm:"column:first_name;type:varchar(100);not null" json:"first_name"`
-	LastName       string    `gorm:"column:last_name;type:varchar(100);not null" json:"last_name"`
-	Email          string    `gorm:"column:email;type:varchar(150);unique;not null" json:"email"`
-	HashedPassword string    `gorm:"column:hashed_password;type:varchar(255);not null" json:"hashed_password"`
-	PhoneNumber    string    `gorm:"column:phone_number;type:varchar(15)" json:"phone_number"`
-	RoleID         uint      `gorm:"column:role_id;not null" json:"role_id"`
-	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UserID           uint       `gorm:"column:user_id;primaryKey;autoIncrement" json:"user_id"`
+	FirstName        string     `gorm:"column:first_name;type:varchar(100);not null" json:"first_name"`
+	LastName         string     `gorm:"column:last_name;type:varchar(100);not null" json:"last_name"`
+	Email            string     `gorm:"column:email;type:varchar(150);unique;not null" json:"email"`
+	HashedPassword   string     `gorm:"column:hashed_password;type:varchar(255);not null" json:"-"`
+	PhoneNumber      string     `gorm:"column:phone_number;type:varchar(15)" json:"phone_number"`
+	RoleID           uint       `gorm:"column:role_id;not null" json:"role_id"`
+	EmailVerified    bool       `gorm:"column:email_verified;not null;default:false" json:"email_verified"`
+	EmailVerifiedAt  *time.Time `gorm:"column:email_verified_at" json:"email_verified_at"`
+	FailedLoginCount int        `gorm:"column:failed_login_count;not null;default:0" json:"-"`
+	LockedUntil      *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	IsActive         bool       `gorm:"column:is_active;not null;default:true" json:"is_active"`
+	DeactivatedAt    *time.Time `gorm:"column:deactivated_at" json:"deactivated_at"`
+	IsServiceAccount bool       `gorm:"column:is_service_account;not null;default:false" json:"is_service_account"`
+	TOTPSecret       string     `gorm:"column:totp_secret;type:varchar(64)" json:"-"`
+	TOTPEnabled      bool       `gorm:"column:totp_enabled;not null;default:false" json:"totp_enabled"`
+	TOTPLastStep     int64      `gorm:"column:totp_last_step;not null;default:0" json:"-"`
+	TokenVersion     int        `gorm:"column:token_version;not null;default:0" json:"-"`
+	CreatedAt        time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 
 	Role RolesMaster `gorm:"foreignKey:RoleID;references:RolesMasterID" json:"role"`
 }
@@ -43,9 +67,20 @@ func (u *User) AfterCreate(tx *gorm.DB) (err error) {
    roles_master
 ========================= */
 
+// Built-in role names seeded into roles_master
+const (
+	RoleAdmin           = "admin"
+	RolePropertyManager = "property_manager"
+	RoleAccountant      = "accountant"
+	RoleViewer          = "viewer"
+)
+
+// RolesMaster.Require2FA makes two-factor authentication mandatory for every
+// user holding the role
 type RolesMaster struct {
 	RolesMasterID uint   `gorm:"column:roles_master_id;primaryKey;autoIncrement" json:"roles_master_id"`
 	Role          string `gorm:"column:role;type:varchar(50);unique;not null" json:"role"`
+	Require2FA    bool   `gorm:"column:require_2fa;not null;default:false" json:"require_2fa"`
 }
 
 func (RolesMaster) TableName() string {
@@ -58,8 +93,8 @@ func (RolesMaster) TableName() string {
 
 type UserRoles struct {
 	UserRolesID uint `gorm:"column:user_roles_id;primaryKey;autoIncrement" json:"user_roles_id"`
-	UserID      uint `gorm:"column:user_id;not null" json:"user_id"`
-	RoleID      uint `gorm:"column:role_id;not null" json:"role_id"`
+	UserID      uint `gorm:"column:user_id;not null;uniqueIndex:idx_user_roles_user_role" json:"user_id"`
+	RoleID      uint `gorm:"column:role_id;not null;uniqueIndex:idx_user_roles_user_role" json:"role_id"`
 }
 
 func (UserRoles) TableName() string {
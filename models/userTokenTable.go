@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Purposes a user token can be issued for
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+/* =========================
+   user_tokens
+   single-use, expiring tokens; only the SHA-256 hash is stored
+========================= */
+
+type UserToken struct {
+	TokenID   uint       `gorm:"column:token_id;primaryKey;autoIncrement" json:"token_id"`
+	UserID    uint       `gorm:"column:user_id;not null;index" json:"user_id"`
+	Purpose   string     `gorm:"column:purpose;type:varchar(30);not null" json:"purpose"`
+	TokenHash string     `gorm:"column:token_hash;type:varchar(64);not null;unique" json:"-"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (UserToken) TableName() string {
+	return "user_tokens"
+}
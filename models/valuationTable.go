@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Valuation sources
+const (
+	ValuationSourceGuidanceValue  = "guidance_value"
+	ValuationSourceMarketEstimate = "market_estimate"
+	ValuationSourceBankAppraisal  = "bank_appraisal"
+	// ValuationSourceManual marks a value typed into the property itself, and
+	// values that were stored before valuation history existed
+	ValuationSourceManual = "manual"
+)
+
+// ValuationSources lists the accepted values of PropertyValuation.Source
+var ValuationSources = []string{
+	ValuationSourceGuidanceValue,
+	ValuationSourceMarketEstimate,
+	ValuationSourceBankAppraisal,
+	ValuationSourceManual,
+}
+
+/* =========================
+   property_valuations
+========================= */
+
+// PropertyValuation is one entry in a property's valuation history. The
+// latest entry by ValuedOn is copied into Property.Value.
+type PropertyValuation struct {
+	ValuationID uint      `gorm:"column:valuation_id;primaryKey;autoIncrement" json:"valuation_id"`
+	PropertyID  uint      `gorm:"column:property_id;not null;index:idx_valuation_property_date,priority:1" json:"property_id"`
+	Amount      Money     `gorm:"column:amount;type:numeric(15,2);not null" json:"amount"`
+	ValuedOn    time.Time `gorm:"column:valued_on;type:date;not null;index:idx_valuation_property_date,priority:2" json:"valued_on"`
+	Source      string    `gorm:"column:source;type:varchar(30);not null" json:"source"`
+	Notes       string    `gorm:"column:notes;type:text" json:"notes"`
+	CreatedBy   uint      `gorm:"column:created_by" json:"created_by"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (PropertyValuation) TableName() string {
+	return "property_valuations"
+}
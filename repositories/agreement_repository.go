@@ -2,15 +2,44 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"property-backend/models"
 )
 
+// ErrAgreementOverlap is matched by an *OverlapError
+var ErrAgreementOverlap = errors.New("agreement overlaps an existing agreement")
+
+// AgreementOverlapConstraint is the exclusion constraint that backs up the
+// overlap check for agreements on the same unit
+const AgreementOverlapConstraint = "agreement_no_overlap"
+
+// exclusionViolation is the Postgres SQLSTATE for a violated exclusion constraint
+const exclusionViolation = "23P01"
+
+// OverlapError reports the existing agreement a new one clashes with
+type OverlapError struct {
+	AgreementID uint
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("agreement overlaps agreement %d on the same property", e.AgreementID)
+}
+
+func (e *OverlapError) Unwrap() error {
+	return ErrAgreementOverlap
+}
+
 // AgreementRepository defines agreement-related data access methods
 type AgreementRepository interface {
 	CreateRental(ctx context.Context, a *models.Agreement) (int64, error)
-	ListAll(ctx context.Context) ([]models.Agreement, error)
+	ListAll(ctx context.Context, page Page) ([]models.Agreement, PageInfo, error)
+	SetNoticeDate(ctx context.Context, id uint, date *time.Time) error
 }
 
 type agreementRepository struct {
@@ -22,17 +51,96 @@ func NewAgreementRepository(db *gorm.DB) AgreementRepository {
 	return &agreementRepository{db: db}
 }
 
+// CreateRental inserts an agreement unless it overlaps another on the same
+// property. Periods include both end dates. Agreements clash when either one
+// covers the whole property (no unit_no) or both are for the same unit. The
+// property row is locked so concurrent inserts are checked one at a time.
+// The agreement's rent schedule is created with it.
 func (r *agreementRepository) CreateRental(ctx context.Context, a *models.Agreement) (int64, error) {
-	if err := r.db.WithContext(ctx).Create(a).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prop models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prop, a.PropertyID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		clash, err := findOverlap(tx, a)
+		if err != nil {
+			return err
+		}
+		if clash != 0 {
+			return &OverlapError{AgreementID: clash}
+		}
+		if err := tx.Create(a).Error; err != nil {
+			return err
+		}
+		return createRentSchedule(tx, a)
+	})
+	if isOverlapViolation(err) {
+		// a writer that skipped the check above got there first; the failed
+		// transaction cannot be queried, so the clash is looked up afresh
+		clash, lookupErr := findOverlap(r.db.WithContext(ctx), a)
+		if lookupErr != nil || clash == 0 {
+			return 0, ErrAgreementOverlap
+		}
+		return 0, &OverlapError{AgreementID: clash}
+	}
+	if err != nil {
 		return 0, err
 	}
 	return int64(a.AgreementID), nil
 }
 
-func (r *agreementRepository) ListAll(ctx context.Context) ([]models.Agreement, error) {
+// findOverlap returns the earliest agreement that clashes with a, or 0
+func findOverlap(tx *gorm.DB, a *models.Agreement) (uint, error) {
+	var clash []uint
+	if err := tx.Model(&models.Agreement{}).
+		Where("property_id = ? AND start_date <= ? AND end_date >= ?", a.PropertyID, a.EndDate, a.StartDate).
+		Where("unit_no = '' OR ? = '' OR unit_no = ?", a.UnitNo, a.UnitNo).
+		Order("start_date").
+		Limit(1).
+		Pluck("agreement_id", &clash).Error; err != nil {
+		return 0, err
+	}
+	if len(clash) == 0 {
+		return 0, nil
+	}
+	return clash[0], nil
+}
+
+// isOverlapViolation reports whether err is Postgres rejecting an insert
+// under AgreementOverlapConstraint
+func isOverlapViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == exclusionViolation && pgErr.ConstraintName == AgreementOverlapConstraint
+}
+
+func (r *agreementRepository) ListAll(ctx context.Context, page Page) ([]models.Agreement, PageInfo, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Agreement{}).Count(&total).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
 	var agreements []models.Agreement
-	if err := r.db.WithContext(ctx).Preload("Property").Find(&agreements).Error; err != nil {
-		return nil, err
+	if err := applyPage(r.db.WithContext(ctx).Preload("Property"), page, "agreement_id").
+		Order("agreement_id").
+		Find(&agreements).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	agreements, info := finishPage(agreements, page, total, func(a *models.Agreement) uint { return a.AgreementID })
+	return agreements, info, nil
+}
+
+// SetNoticeDate records when notice to vacate was given; nil withdraws it
+func (r *agreementRepository) SetNoticeDate(ctx context.Context, id uint, date *time.Time) error {
+	res := r.db.WithContext(ctx).Model(&models.Agreement{}).
+		Where("agreement_id = ?", id).
+		Update("notice_date", date)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
 	}
-	return agreements, nil
+	return nil
 }
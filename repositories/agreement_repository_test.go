@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsOverlapViolation(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"overlap constraint", &pgconn.PgError{Code: "23P01", ConstraintName: AgreementOverlapConstraint}, true},
+		{"wrapped by the transaction", fmt.Errorf("insert agreement: %w", &pgconn.PgError{Code: "23P01", ConstraintName: AgreementOverlapConstraint}), true},
+		{"another exclusion constraint", &pgconn.PgError{Code: "23P01", ConstraintName: "booking_no_overlap"}, false},
+		{"unique violation", &pgconn.PgError{Code: "23505", ConstraintName: AgreementOverlapConstraint}, false},
+		{"app overlap check", &OverlapError{AgreementID: 12}, false},
+		{"other error", errors.New("connection reset"), false},
+		{"no error", nil, false},
+	} {
+		if got := isOverlapViolation(tc.err); got != tc.want {
+			t.Errorf("%s: %t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOverlapErrorNamesTheClash(t *testing.T) {
+	err := fmt.Errorf("create: %w", &OverlapError{AgreementID: 12})
+	if !errors.Is(err, ErrAgreementOverlap) {
+		t.Error("OverlapError does not match ErrAgreementOverlap")
+	}
+	var overlap *OverlapError
+	if !errors.As(err, &overlap) || overlap.AgreementID != 12 {
+		t.Errorf("OverlapError lost the clashing agreement: %v", err)
+	}
+}
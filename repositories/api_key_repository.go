@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"property-backend/models"
+)
+
+// APIKeyRepository defines data access for service account API keys
+type APIKeyRepository interface {
+	Create(ctx context.Context, k *models.APIKey) error
+	GetByID(ctx context.Context, id uint) (*models.APIKey, error)
+	GetActiveByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	ListByUser(ctx context.Context, userID uint) ([]models.APIKey, error)
+	Revoke(ctx context.Context, id uint) error
+	Rotate(ctx context.Context, oldID uint, replacement *models.APIKey) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository constructs an APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, k *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(k).Error
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetActiveByHash finds an unrevoked key and preloads the service account it belongs to
+func (r *apiKeyRepository) GetActiveByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("key_hash = ? AND revoked_at IS NULL", keyHash).
+		First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("api_key_id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("api_key_id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Rotate revokes a key and stores its replacement in one transaction
+func (r *apiKeyRepository) Rotate(ctx context.Context, oldID uint, replacement *models.APIKey) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.APIKey{}).
+			Where("api_key_id = ? AND revoked_at IS NULL", oldID).
+			Update("revoked_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Create(replacement).Error
+	})
+}
+
+// TouchLastUsed records key usage, writing at most once a minute per key
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).
+		Where("api_key_id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-time.Minute)).
+		Update("last_used_at", at).Error
+}
@@ -10,7 +10,7 @@ import (
 // AssetRepository defines asset-related data access methods
 type AssetRepository interface {
 	Create(ctx context.Context, a *models.Asset) (int64, error)
-	ListAll(ctx context.Context) ([]models.Asset, error)
+	ListAll(ctx context.Context, page Page) ([]models.Asset, PageInfo, error)
 }
 
 type assetRepository struct {
@@ -29,10 +29,17 @@ func (r *assetRepository) Create(ctx context.Context, a *models.Asset) (int64, e
 	return int64(a.AssetID), nil
 }
 
-func (r *assetRepository) ListAll(ctx context.Context) ([]models.Asset, error) {
+func (r *assetRepository) ListAll(ctx context.Context, page Page) ([]models.Asset, PageInfo, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Asset{}).Count(&total).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
 	var assets []models.Asset
-	if err := r.db.WithContext(ctx).Find(&assets).Error; err != nil {
-		return nil, err
+	if err := applyPage(r.db.WithContext(ctx), page, "asset_id").
+		Order("asset_id").
+		Find(&assets).Error; err != nil {
+		return nil, PageInfo{}, err
 	}
-	return assets, nil
+	assets, info := finishPage(assets, page, total, func(a *models.Asset) uint { return a.AssetID })
+	return assets, info, nil
 }
@@ -2,16 +2,33 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"property-backend/models"
 )
 
+// UserFilter narrows a user listing
+type UserFilter struct {
+	Search         string // matches first name, last name or email
+	Active         *bool
+	ServiceAccount *bool
+}
+
 // AuthRepository defines auth/user-related data access methods
 type AuthRepository interface {
 	Create(ctx context.Context, u *models.User) (int64, error)
+	CreateWithRoles(ctx context.Context, u *models.User, extraRoleIDs []uint) (int64, error)
 	GetByID(ctx context.Context, id int64) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetRoleNames(ctx context.Context, userID uint) ([]string, error)
+	IncrementFailedLogins(ctx context.Context, userID uint) (int, error)
+	LockUntil(ctx context.Context, userID uint, until time.Time) error
+	ResetFailedLogins(ctx context.Context, userID uint) error
+	List(ctx context.Context, filter UserFilter, page Page) ([]models.User, PageInfo, error)
+	Update(ctx context.Context, userID uint, fields map[string]interface{}) error
+	SetActive(ctx context.Context, userID uint, active bool) error
 }
 
 type authRepository struct {
@@ -30,6 +47,30 @@ func (r *authRepository) Create(ctx context.Context, u *models.User) (int64, err
 	return int64(u.UserID), nil
 }
 
+// CreateWithRoles creates the user (u.RoleID becomes the primary role) and
+// grants any additional roles in the same transaction
+func (r *authRepository) CreateWithRoles(ctx context.Context, u *models.User, extraRoleIDs []uint) (int64, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(u).Error; err != nil {
+			return err
+		}
+		for _, roleID := range extraRoleIDs {
+			if roleID == u.RoleID {
+				continue
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.UserRoles{UserID: u.UserID, RoleID: roleID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(u.UserID), nil
+}
+
 func (r *authRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
 	var user models.User
 	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
@@ -45,3 +86,76 @@ func (r *authRepository) GetByEmail(ctx context.Context, email string) (*models.
 	}
 	return &user, nil
 }
+
+func (r *authRepository) GetRoleNames(ctx context.Context, userID uint) ([]string, error) {
+	var roles []string
+	if err := r.db.WithContext(ctx).
+		Model(&models.UserRoles{}).
+		Joins("JOIN roles_master ON roles_master.roles_master_id = user_roles.role_id").
+		Where("user_roles.user_id = ?", userID).
+		Pluck("roles_master.role", &roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// IncrementFailedLogins bumps the user's consecutive failure counter and returns the new value
+func (r *authRepository) IncrementFailedLogins(ctx context.Context, userID uint) (int, error) {
+	var count int
+	if err := r.db.WithContext(ctx).
+		Raw(`UPDATE "user" SET failed_login_count = failed_login_count + 1 WHERE user_id = ? RETURNING failed_login_count`, userID).
+		Scan(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *authRepository) LockUntil(ctx context.Context, userID uint, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", userID).Update("locked_until", until).Error
+}
+
+// ResetFailedLogins clears the failure counter and any lock
+func (r *authRepository) ResetFailedLogins(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"failed_login_count": 0, "locked_until": nil}).Error
+}
+
+func (r *authRepository) List(ctx context.Context, filter UserFilter, page Page) ([]models.User, PageInfo, error) {
+	q := r.db.WithContext(ctx).Model(&models.User{})
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		q = q.Where("first_name ILIKE ? OR last_name ILIKE ? OR email ILIKE ?", like, like, like)
+	}
+	if filter.Active != nil {
+		q = q.Where("is_active = ?", *filter.Active)
+	}
+	if filter.ServiceAccount != nil {
+		q = q.Where("is_service_account = ?", *filter.ServiceAccount)
+	}
+
+	q = q.Session(&gorm.Session{})
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	var users []models.User
+	if err := applyPage(q, page, "user_id").Order("user_id").Find(&users).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	users, info := finishPage(users, page, total, func(u *models.User) uint { return u.UserID })
+	return users, info, nil
+}
+
+func (r *authRepository) Update(ctx context.Context, userID uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", userID).Updates(fields).Error
+}
+
+// SetActive deactivates or reactivates a user. The row is never deleted
+// because properties keep pointing at it through property.user_id.
+func (r *authRepository) SetActive(ctx context.Context, userID uint, active bool) error {
+	fields := map[string]interface{}{"is_active": active, "deactivated_at": nil}
+	if !active {
+		fields["deactivated_at"] = time.Now()
+	}
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("user_id = ?", userID).Updates(fields).Error
+}
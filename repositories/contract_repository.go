@@ -10,8 +10,8 @@ import (
 // ContractRepository defines contract-related data access methods
 type ContractRepository interface {
 	Create(ctx context.Context, c *models.Contract) (int64, error)
-	ListAll(ctx context.Context) ([]models.Contract, error)
-	ListByType(ctx context.Context, contractType string) ([]models.Contract, error)
+	ListAll(ctx context.Context, page Page) ([]models.Contract, PageInfo, error)
+	ListByType(ctx context.Context, contractType string, page Page) ([]models.Contract, PageInfo, error)
 }
 
 type contractRepository struct {
@@ -30,23 +30,29 @@ func (r *contractRepository) Create(ctx context.Context, c *models.Contract) (in
 	return int64(c.ContractID), nil
 }
 
-func (r *contractRepository) ListAll(ctx context.Context) ([]models.Contract, error) {
-	var contracts []models.Contract
-	if err := r.db.WithContext(ctx).Preload("Asset").Preload("ContractType").Find(&contracts).Error; err != nil {
-		return nil, err
-	}
-	return contracts, nil
+func (r *contractRepository) ListAll(ctx context.Context, page Page) ([]models.Contract, PageInfo, error) {
+	return r.list(ctx, r.db.WithContext(ctx).Model(&models.Contract{}), page)
 }
 
-func (r *contractRepository) ListByType(ctx context.Context, contractType string) ([]models.Contract, error) {
+func (r *contractRepository) ListByType(ctx context.Context, contractType string, page Page) ([]models.Contract, PageInfo, error) {
+	q := r.db.WithContext(ctx).Model(&models.Contract{}).
+		Joins("JOIN contract_type_master ON contract_type_master.contract_type_id = contracts.contract_type_id").
+		Where("contract_type_master.contract_type_name = ?", contractType)
+	return r.list(ctx, q, page)
+}
+
+func (r *contractRepository) list(ctx context.Context, q *gorm.DB, page Page) ([]models.Contract, PageInfo, error) {
+	q = q.Session(&gorm.Session{})
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
 	var contracts []models.Contract
-	if err := r.db.WithContext(ctx).
-		Preload("Asset").
-		Preload("ContractType").
-		Joins("ContractType").
-		Where("contract_type_master.contract_type_name = ?", contractType).
+	if err := applyPage(q.Select("contracts.*").Preload("Asset").Preload("ContractType"), page, "contracts.contract_id").
+		Order("contracts.contract_id").
 		Find(&contracts).Error; err != nil {
-		return nil, err
+		return nil, PageInfo{}, err
 	}
-	return contracts, nil
+	contracts, info := finishPage(contracts, page, total, func(c *models.Contract) uint { return c.ContractID })
+	return contracts, info, nil
 }
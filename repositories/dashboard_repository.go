@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"property-backend/dto"
+	"property-backend/models"
+)
+
+// DashboardRepository runs the aggregate queries behind the portfolio
+// dashboard. Every query skips soft-deleted properties.
+type DashboardRepository interface {
+	CountByPropertyType(ctx context.Context) ([]dto.GroupCount, error)
+	CountByDistrict(ctx context.Context) ([]dto.GroupCount, error)
+	PortfolioValue(ctx context.Context) (total models.Money, unvalued int64, err error)
+	ActiveRent(ctx context.Context, at time.Time) (monthlyRent float64, occupied int64, err error)
+	AgreementExpiries(ctx context.Context, from time.Time) (dto.ExpiryWindows, error)
+	ContractExpiries(ctx context.Context, from time.Time, contractType string) (dto.ExpiryWindows, error)
+	TaxUnpaidCount(ctx context.Context) (int64, error)
+}
+
+type dashboardRepository struct {
+	db *gorm.DB
+}
+
+// NewDashboardRepository constructs a DashboardRepository
+func NewDashboardRepository(db *gorm.DB) DashboardRepository {
+	return &dashboardRepository{db: db}
+}
+
+// expiryWindows is scanned from the FILTER counts of an expiry query
+type expiryWindows struct {
+	D30 int64 `gorm:"column:d30"`
+	D60 int64 `gorm:"column:d60"`
+	D90 int64 `gorm:"column:d90"`
+}
+
+const expirySelect = "COUNT(*) FILTER (WHERE %[1]s < ?) AS d30, " +
+	"COUNT(*) FILTER (WHERE %[1]s < ?) AS d60, " +
+	"COUNT(*) FILTER (WHERE %[1]s < ?) AS d90"
+
+func (r *dashboardRepository) CountByPropertyType(ctx context.Context) ([]dto.GroupCount, error) {
+	rows := []dto.GroupCount{}
+	err := r.db.WithContext(ctx).Model(&models.Property{}).
+		Select("COALESCE(property_type_master.property_type_id, 0) AS id, " +
+			"COALESCE(property_type_master.property_type_name, '') AS name, COUNT(*) AS count").
+		Joins("LEFT JOIN property_type_master ON property_type_master.property_type_id = property.property_type_id").
+		Group("property_type_master.property_type_id, property_type_master.property_type_name").
+		Order("count DESC, name").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *dashboardRepository) CountByDistrict(ctx context.Context) ([]dto.GroupCount, error) {
+	rows := []dto.GroupCount{}
+	err := r.db.WithContext(ctx).Model(&models.Property{}).
+		Select("COALESCE(district_masters.district_id, 0) AS id, " +
+			"COALESCE(district_masters.district_name, '') AS name, COUNT(*) AS count").
+		Joins("LEFT JOIN addresses ON addresses.address_id = property.address_id").
+		Joins("LEFT JOIN taluk_masters ON taluk_masters.taluk_id = addresses.taluk_id").
+		Joins("LEFT JOIN district_masters ON district_masters.district_id = taluk_masters.district_id").
+		Group("district_masters.district_id, district_masters.district_name").
+		Order("count DESC, name").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *dashboardRepository) PortfolioValue(ctx context.Context) (models.Money, int64, error) {
+	var row struct {
+		Total    models.Money `gorm:"column:total"`
+		Unvalued int64        `gorm:"column:unvalued"`
+	}
+	err := r.db.WithContext(ctx).Model(&models.Property{}).
+		Select("COALESCE(SUM(property.value), 0) AS total, COUNT(*) FILTER (WHERE property.value IS NULL) AS unvalued").
+		Scan(&row).Error
+	return row.Total, row.Unvalued, err
+}
+
+// ActiveRent sums the rent, after escalations, of agreements running at the
+// given time and counts the distinct properties they cover
+func (r *dashboardRepository) ActiveRent(ctx context.Context, at time.Time) (float64, int64, error) {
+	var agreements []models.Agreement
+	err := r.db.WithContext(ctx).
+		Select("agreement.agreement_id, agreement.property_id, agreement.rent, agreement.start_date, "+
+			"agreement.escalation_type, agreement.escalation_value, agreement.escalation_interval_months, agreement.escalation_cap").
+		Joins("JOIN property ON property.property_id = agreement.property_id AND property.deleted_at IS NULL").
+		Where("agreement.start_date <= ? AND agreement.end_date >= ?", at, at).
+		Find(&agreements).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	var rent float64
+	properties := map[uint]bool{}
+	for i := range agreements {
+		rent += agreements[i].RentOn(at)
+		properties[agreements[i].PropertyID] = true
+	}
+	return rent, int64(len(properties)), nil
+}
+
+// AgreementExpiries counts agreements ending within 30, 60 and 90 days of from
+func (r *dashboardRepository) AgreementExpiries(ctx context.Context, from time.Time) (dto.ExpiryWindows, error) {
+	q := r.db.WithContext(ctx).Model(&models.Agreement{}).
+		Joins("JOIN property ON property.property_id = agreement.property_id AND property.deleted_at IS NULL")
+	return scanExpiries(q, "agreement.end_date", from)
+}
+
+// ContractExpiries counts contracts ending within 30, 60 and 90 days of from,
+// optionally only those of one contract type
+func (r *dashboardRepository) ContractExpiries(ctx context.Context, from time.Time, contractType string) (dto.ExpiryWindows, error) {
+	q := r.db.WithContext(ctx).Model(&models.Contract{}).
+		Joins("JOIN assets ON assets.asset_id = contracts.asset_id").
+		Joins("JOIN property ON property.property_id = assets.property_id AND property.deleted_at IS NULL")
+	if contractType != "" {
+		q = q.Joins("JOIN contract_type_master ON contract_type_master.contract_type_id = contracts.contract_type_id").
+			Where("contract_type_master.contract_type_name = ?", contractType)
+	}
+	return scanExpiries(q, "contracts.end_date", from)
+}
+
+func (r *dashboardRepository) TaxUnpaidCount(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Property{}).
+		Joins("LEFT JOIN property_tax_details ON property_tax_details.property_id = property.property_id").
+		Where("COALESCE(property_tax_details.tax_paid, false) = false").
+		Count(&count).Error
+	return count, err
+}
+
+func scanExpiries(q *gorm.DB, endCol string, from time.Time) (dto.ExpiryWindows, error) {
+	var row expiryWindows
+	err := q.Select(fmt.Sprintf(expirySelect, endCol),
+		from.AddDate(0, 0, 30), from.AddDate(0, 0, 60), from.AddDate(0, 0, 90)).
+		Where(endCol+" >= ?", from).
+		Scan(&row).Error
+	return dto.ExpiryWindows{Within30Days: row.D30, Within60Days: row.D60, Within90Days: row.D90}, err
+}
@@ -4,3 +4,6 @@ import "errors"
 
 // ErrNotImplemented is returned by repository methods that are not yet implemented
 var ErrNotImplemented = errors.New("not implemented")
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"property-backend/models"
+)
+
+// MFARepository defines data access for TOTP secrets and recovery codes
+type MFARepository interface {
+	SetSecret(ctx context.Context, userID uint, secret string) error
+	Enable(ctx context.Context, userID uint, step int64, codeHashes []string) error
+	Disable(ctx context.Context, userID uint) error
+	UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	ConsumeRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository constructs an MFARepository
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// SetSecret stores a new, not yet confirmed, TOTP secret
+func (r *mfaRepository) SetSecret(ctx context.Context, userID uint, secret string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).
+		Where("user_id = ? AND totp_enabled = ?", userID, false).
+		Updates(map[string]interface{}{"totp_secret": secret, "totp_last_step": 0}).Error
+}
+
+// Enable switches two-factor sign in on and replaces the recovery codes.
+// step is the time step of the confirmation code so it cannot be reused.
+func (r *mfaRepository) Enable(ctx context.Context, userID uint, step int64, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"totp_enabled": true, "totp_last_step": step}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Disable clears the TOTP secret and all recovery codes
+func (r *mfaRepository) Disable(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"totp_enabled": false, "totp_secret": "", "totp_last_step": 0}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
+
+// UseTOTPStep records step as the last accepted TOTP step. It returns false
+// when a code from this or a later step was already accepted (a replay).
+func (r *mfaRepository) UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("user_id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// ConsumeRecoveryCode marks a matching unused code as used; false means no such code
+func (r *mfaRepository) ConsumeRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left
+func (r *mfaRepository) CountRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&n).Error
+	return n, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.RecoveryCode, 0, len(codeHashes))
+	for _, h := range codeHashes {
+		codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: h})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
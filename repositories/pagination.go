@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultPageLimit is used when a caller does not ask for a page size
+	DefaultPageLimit = 20
+	// MaxPageLimit caps every listing regardless of what the caller asks for
+	MaxPageLimit = 100
+
+	cursorPrefix = "id:"
+)
+
+var (
+	// ErrInvalidCursor is returned for a cursor that was not produced by EncodeCursor
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorUnsupported is returned by listings that are not ordered by primary key
+	ErrCursorUnsupported = errors.New("this listing does not support cursors, use offset")
+)
+
+// Page selects one slice of a listing. Listings are ordered by primary key
+// unless stated otherwise. AfterID (decoded from an opaque cursor) switches
+// to keyset pagination and takes precedence over Offset.
+type Page struct {
+	Limit   int
+	Offset  int
+	AfterID uint
+}
+
+// PageInfo describes the page that was returned. Total counts every match,
+// ignoring Offset and AfterID. NextCursor is empty on the last page.
+type PageInfo struct {
+	Total      int64
+	NextCursor string
+}
+
+// EncodeCursor turns the last primary key of a page into an opaque cursor
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(uint64(id), 10)))
+}
+
+// DecodeCursor reverses EncodeCursor
+func DecodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(string(raw), cursorPrefix), 10, 64)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
+
+// limit applies the default and the hard upper limit
+func (p Page) limit() int {
+	if p.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	return min(p.Limit, MaxPageLimit)
+}
+
+// applyPage restricts q to the page. One extra row is fetched so finishPage
+// can tell whether another page follows.
+func applyPage(q *gorm.DB, p Page, idColumn string) *gorm.DB {
+	if p.AfterID > 0 {
+		q = q.Where(idColumn+" > ?", p.AfterID)
+	} else if p.Offset > 0 {
+		q = q.Offset(p.Offset)
+	}
+	return q.Limit(p.limit() + 1)
+}
+
+// finishPage drops the look-ahead row fetched by applyPage and builds the PageInfo
+func finishPage[T any](rows []T, p Page, total int64, id func(*T) uint) ([]T, PageInfo) {
+	info := PageInfo{Total: total}
+	if len(rows) > p.limit() {
+		rows = rows[:p.limit()]
+		info.NextCursor = EncodeCursor(id(&rows[len(rows)-1]))
+	}
+	return rows, info
+}
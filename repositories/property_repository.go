@@ -2,22 +2,88 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"sort"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"property-backend/dto"
 	"property-backend/models"
+	"property-backend/utils"
 )
 
+// PropertyFilter narrows a property search; zero values do not filter.
+// Location names match case-insensitively.
+type PropertyFilter struct {
+	Search          string // matches the property name
+	PropertyTypeID  uint
+	PropertyType    string
+	State           string
+	District        string
+	Taluk           string
+	Village         string
+	Pincode         string
+	UserID          uint
+	MinValue        *float64
+	MaxValue        *float64
+	TaxPaid         *bool
+	Converted       *bool
+	AcquisitionType string
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	// Occupancy keeps properties whose current status is one of these
+	Occupancy []string
+	// Sort is one of PropertySortFields, optionally prefixed with "-" for descending
+	Sort string
+}
+
+// PropertySortFields maps the sort keys accepted by Search to SQL expressions
+var PropertySortFields = map[string]string{
+	"id":         "property.property_id",
+	"name":       "property.property_name",
+	"created_at": "property.created_at",
+	"value":      "property.value",
+}
+
+// convertedValues are the spellings of "yes" found in property_land_details.converted
+var convertedValues = []string{"yes", "y", "true", "1"}
+
 // PropertyRepository defines property-related data access methods
 type PropertyRepository interface {
 	Total(ctx context.Context) (int64, error)
-	ActiveRentalCount(ctx context.Context) (int64, error)
-	Create(ctx context.Context, req interface{}) (int64, error)
-	ListByType(ctx context.Context, propertyType string) ([]models.Property, error)
+	OccupancyCounts(ctx context.Context) (map[string]int64, error)
+	Create(ctx context.Context, userID uint, in *dto.PropertyCreate) (int64, error)
+	Search(ctx context.Context, filter PropertyFilter, page Page) ([]models.Property, PageInfo, error)
+	GetByID(ctx context.Context, id uint) (*models.Property, error)
+	Update(ctx context.Context, actorID, id uint, upd *dto.PropertyUpdate, replace bool) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	Purge(ctx context.Context, id uint) error
+	References(ctx context.Context, id uint, activeOnly bool) (agreements, assets int64, err error)
 }
 
+// ErrAddressLocationRequired is returned when a property without an address
+// is given address fields but no location to attach them to
+var ErrAddressLocationRequired = errors.New("address requires country, state, district and taluk names")
+
+// ErrKarabExceedsExtent is returned when an update leaves the karab portion
+// of the land larger than its extent
+var ErrKarabExceedsExtent = errors.New("karab exceeds the land extent")
+
+var (
+	// ErrInvalidSort is returned for a sort key that is not in PropertySortFields
+	ErrInvalidSort = errors.New("invalid sort field")
+	// ErrCursorWithSort is returned when a cursor is combined with a non-default sort
+	ErrCursorWithSort = errors.New("cursor pagination only supports the default sort")
+	// ErrPropertyInUse is returned when agreements or assets still reference a property
+	ErrPropertyInUse = errors.New("property is referenced by agreements or assets")
+	// ErrPropertyNotDeleted is returned when purging a property that was not soft-deleted first
+	ErrPropertyNotDeleted = errors.New("property must be deleted before it can be purged")
+)
+
 type propertyRepository struct {
 	db *gorm.DB
 }
@@ -35,272 +101,780 @@ func (r *propertyRepository) Total(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-func (r *propertyRepository) ActiveRentalCount(ctx context.Context) (int64, error) {
-	var count int64
-	now := time.Now()
-	if err := r.db.WithContext(ctx).
-		Model(&models.Agreement{}).
-		Where("start_date <= ? AND end_date >= ?", now, now).
-		Count(&count).Error; err != nil {
+// occupancyJoin summarises, per property, the agreements running on a day:
+// how many distinct units are let, whether one covers the whole property and
+// whether all of them have been given notice
+const occupancyJoin = `LEFT JOIN (
+	SELECT property_id,
+		COUNT(DISTINCT NULLIF(unit_no, '')) AS let_units,
+		BOOL_OR(unit_no = '') AS whole,
+		BOOL_AND(notice_date IS NOT NULL) AS on_notice
+	FROM agreement
+	WHERE start_date <= ? AND end_date >= ?
+	GROUP BY property_id
+) occupancy ON occupancy.property_id = property.property_id`
+
+// occupancyStatusExpr computes a property's occupancy status from occupancyJoin
+var occupancyStatusExpr = fmt.Sprintf(`CASE
+	WHEN occupancy.property_id IS NULL THEN '%s'
+	WHEN occupancy.on_notice THEN '%s'
+	WHEN occupancy.whole OR occupancy.let_units >= COALESCE(property.unit_count, 1) THEN '%s'
+	ELSE '%s' END`,
+	models.OccupancyVacant, models.OccupancyNoticePeriod, models.OccupancyLet, models.OccupancyPartiallyLet)
+
+// withOccupancy joins the occupancy summary on the day of the given time onto
+// a property query. Agreement dates are stored at midnight and the end date is
+// inclusive, so comparing with the instant would free a property for most of
+// an agreement's last day.
+func withOccupancy(q *gorm.DB, at time.Time) *gorm.DB {
+	day := utils.DateOf(at)
+	return q.Joins(occupancyJoin, day, day)
+}
+
+// OccupancyCounts counts properties by their current occupancy status; every
+// status is present in the result
+func (r *propertyRepository) OccupancyCounts(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := withOccupancy(r.db.WithContext(ctx).Model(&models.Property{}), time.Now()).
+		Select(occupancyStatusExpr + " AS status, COUNT(*) AS count").
+		Group(occupancyStatusExpr).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(models.OccupancyStatuses))
+	for _, s := range models.OccupancyStatuses {
+		counts[s] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// Create inserts a property owned by userID together with its address and
+// all five detail rows in one transaction
+func (r *propertyRepository) Create(ctx context.Context, userID uint, in *dto.PropertyCreate) (int64, error) {
+	var property models.Property
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. Get or create PropertyType
+		propertyTypeID := in.PropertyTypeID
+		if propertyTypeID == 0 && in.PropertyTypeName != "" {
+			id, err := getOrCreatePropertyType(tx, in.PropertyTypeName)
+			if err != nil {
+				return err
+			}
+			propertyTypeID = id
+		}
+
+		// 2. Create Address hierarchy (Country -> State -> District -> Taluk -> Address)
+		var addressID uint
+		a := in.Address
+		talukID, err := resolveTaluk(tx, a.CountryName, a.StateName, a.DistrictName, a.TalukName)
+		if err != nil {
+			return err
+		}
+		if talukID > 0 {
+			address := models.Address{
+				TalukID:        talukID,
+				Hobli:          a.Hobli,
+				Village:        a.Village,
+				StreetAddress:  a.StreetAddress,
+				Pincode:        a.Pincode,
+				LatCoordinate:  a.LatCoordinate,
+				LongCoordinate: a.LongCoordinate,
+			}
+			if err := tx.Create(&address).Error; err != nil {
+				return fmt.Errorf("failed to create address: %w", err)
+			}
+			addressID = address.AddressID
+		}
+
+		// 3. Create Property
+		property = models.Property{
+			PropertyName:   in.PropertyName,
+			PropertyTypeID: propertyTypeID,
+			AddressID:      addressID,
+			UserID:         userID,
+			Value:          in.Value,
+			Income:         in.Income,
+			OriginalDeed:   in.OriginalDeed,
+			UnitCount:      in.UnitCount,
+		}
+		if err := tx.Create(&property).Error; err != nil {
+			return fmt.Errorf("failed to create property: %w", err)
+		}
+		if in.Value != nil {
+			if err := recordValuation(tx, manualValuation(property.PropertyID, *in.Value, userID)); err != nil {
+				return err
+			}
+		}
+
+		// 4. Create the detail rows
+		l, t, o, b, m := in.Land, in.Tax, in.Ownership, in.Building, in.Media
+		details := []struct {
+			name  string
+			model interface{}
+		}{
+			{"land details", &models.PropertyLandDetails{
+				PropertyID: property.PropertyID,
+				Rtc:        l.Rtc,
+				Ec:         l.Ec,
+				SyNo:       l.SyNo,
+				KhataNo:    l.KhataNo,
+				MrNo:       l.MrNo,
+				Acre:       l.Acre,
+				Gunte:      l.Gunte,
+				Karab:      l.Karab,
+				Converted:  l.Converted,
+				Purpose:    l.Purpose,
+				KhuskiTari: l.KhuskiTari,
+			}},
+			{"tax details", &models.PropertyTaxDetails{
+				PropertyID:  property.PropertyID,
+				TaxPaid:     t.TaxPaid,
+				ReceiptNo:   t.ReceiptNo,
+				PrevAmount:  t.PrevAmount,
+				CurrAmount:  t.CurrAmount,
+				ReceiptLink: t.ReceiptLink,
+			}},
+			{"ownership details", &models.PropertyOwnershipDetails{
+				PropertyID:          property.PropertyID,
+				ReceivedFrom:        o.ReceivedFrom,
+				AcquisitionType:     o.AcquisitionType,
+				RegistrationDetails: o.RegistrationDetails,
+				Title:               o.Title,
+				Incharge:            o.Incharge,
+				PhoneNumber:         o.PhoneNumber,
+			}},
+			{"building details", &models.PropertyBuildingDetails{
+				PropertyID:         property.PropertyID,
+				PlotSize:           b.PlotSize,
+				BuiltUpArea:        b.BuiltUpArea,
+				YearOfConstruction: b.YearOfConstruction,
+				ApplicationNo:      b.ApplicationNo,
+			}},
+			{"media", &models.PropertyMedia{
+				PropertyID:      property.PropertyID,
+				ScannedDeedLink: m.ScannedDeedLink,
+				PhotoLink:       m.PhotoLink,
+				Remarks:         m.Remarks,
+			}},
+		}
+		for _, d := range details {
+			if err := tx.Create(d.model).Error; err != nil {
+				return fmt.Errorf("failed to create %s: %w", d.name, err)
+			}
+		}
+		_, err = refreshLandArea(tx.Where("property_id = ?", property.PropertyID))
+		return err
+	})
+	if err != nil {
 		return 0, err
 	}
-	return count, nil
+	return int64(property.PropertyID), nil
 }
 
-func (r *propertyRepository) Create(ctx context.Context, req interface{}) (int64, error) {
-	// Type assert to get the request object
-	requestData := req.(map[string]interface{})
-
-	// Start transaction
-	tx := r.db.WithContext(ctx).Begin()
-
-	// 1. Get or create PropertyType
-	propertyTypeID := uint(0)
-	if ptID, ok := requestData["property_type_id"].(float64); ok && ptID > 0 {
-		propertyTypeID = uint(ptID)
-	} else if ptName, ok := requestData["property_type_name"].(string); ok && ptName != "" {
-		var propType models.PropertyTypeMaster
-		if err := tx.Where("property_type_name = ?", ptName).First(&propType).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				propType.PropertyTypeName = ptName
-				if err := tx.Create(&propType).Error; err != nil {
-					tx.Rollback()
-					return 0, fmt.Errorf("failed to create property type: %w", err)
-				}
-			} else {
-				tx.Rollback()
-				return 0, err
+// Search lists properties matching the filter with their type and address
+// hierarchy, returning one page and the total number of matches. Cursors
+// only work with the default sort by ID; other sorts page by offset.
+func (r *propertyRepository) Search(ctx context.Context, filter PropertyFilter, page Page) ([]models.Property, PageInfo, error) {
+	order, err := propertyOrder(filter.Sort)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+	keyset := filter.Sort == "" || filter.Sort == "id"
+	if page.AfterID > 0 && !keyset {
+		return nil, PageInfo{}, ErrCursorWithSort
+	}
+	// Session lets the filtered query be reused for both the count and the page
+	q := withOccupancy(r.db.WithContext(ctx).Model(&models.Property{}), time.Now())
+	q = applyPropertyFilter(q, filter).Session(&gorm.Session{})
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	var props []models.Property
+	if err := applyPage(q.Select("property.*, "+occupancyStatusExpr+" AS occupancy_status"), page, "property.property_id").
+		Preload("PropertyType").
+		Preload("Address.Taluk.District.State.Country").
+		Preload("LandDetails").
+		Preload("BuildingDetails").
+		Order(order).
+		Find(&props).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	props, info := finishPage(props, page, total, func(p *models.Property) uint { return p.PropertyID })
+	if !keyset {
+		info.NextCursor = ""
+	}
+	return props, info, nil
+}
+
+// applyPropertyFilter narrows a property query; it expects withOccupancy to
+// have been applied already
+func applyPropertyFilter(q *gorm.DB, f PropertyFilter) *gorm.DB {
+	if f.Search != "" {
+		q = q.Where("property.property_name ILIKE ?", "%"+f.Search+"%")
+	}
+	if f.PropertyTypeID > 0 {
+		q = q.Where("property.property_type_id = ?", f.PropertyTypeID)
+	}
+	if f.PropertyType != "" {
+		q = q.Joins("JOIN property_type_master ON property_type_master.property_type_id = property.property_type_id").
+			Where("LOWER(property_type_master.property_type_name) = LOWER(?)", f.PropertyType)
+	}
+
+	if f.Village != "" || f.Pincode != "" || f.Taluk != "" || f.District != "" || f.State != "" {
+		q = q.Joins("JOIN addresses ON addresses.address_id = property.address_id")
+		if f.Village != "" {
+			q = q.Where("LOWER(addresses.village) = LOWER(?)", f.Village)
+		}
+		if f.Pincode != "" {
+			q = q.Where("addresses.pincode = ?", f.Pincode)
+		}
+	}
+	if f.Taluk != "" || f.District != "" || f.State != "" {
+		q = q.Joins("JOIN taluk_masters ON taluk_masters.taluk_id = addresses.taluk_id")
+		if f.Taluk != "" {
+			q = q.Where("LOWER(taluk_masters.taluk_name) = LOWER(?)", f.Taluk)
+		}
+	}
+	if f.District != "" || f.State != "" {
+		q = q.Joins("JOIN district_masters ON district_masters.district_id = taluk_masters.district_id")
+		if f.District != "" {
+			q = q.Where("LOWER(district_masters.district_name) = LOWER(?)", f.District)
+		}
+	}
+	if f.State != "" {
+		q = q.Joins("JOIN state_masters ON state_masters.state_id = district_masters.state_id").
+			Where("LOWER(state_masters.state_name) = LOWER(?)", f.State)
+	}
+
+	if f.UserID > 0 {
+		q = q.Where("property.user_id = ?", f.UserID)
+	}
+	if f.MinValue != nil {
+		q = q.Where("property.value >= ?", *f.MinValue)
+	}
+	if f.MaxValue != nil {
+		q = q.Where("property.value <= ?", *f.MaxValue)
+	}
+	if f.TaxPaid != nil {
+		q = q.Joins("LEFT JOIN property_tax_details ON property_tax_details.property_id = property.property_id").
+			Where("COALESCE(property_tax_details.tax_paid, false) = ?", *f.TaxPaid)
+	}
+	if f.Converted != nil {
+		q = q.Joins("LEFT JOIN property_land_details ON property_land_details.property_id = property.property_id")
+		if *f.Converted {
+			q = q.Where("LOWER(TRIM(property_land_details.converted)) IN ?", convertedValues)
+		} else {
+			q = q.Where("property_land_details.converted IS NULL OR LOWER(TRIM(property_land_details.converted)) NOT IN ?", convertedValues)
+		}
+	}
+	if f.AcquisitionType != "" {
+		q = q.Joins("JOIN property_ownership_details ON property_ownership_details.property_id = property.property_id").
+			Where("LOWER(property_ownership_details.acquisition_type) = LOWER(?)", f.AcquisitionType)
+	}
+	if f.CreatedFrom != nil {
+		q = q.Where("property.created_at >= ?", *f.CreatedFrom)
+	}
+	if f.CreatedTo != nil {
+		q = q.Where("property.created_at < ?", *f.CreatedTo)
+	}
+	if len(f.Occupancy) > 0 {
+		q = q.Where(occupancyStatusExpr+" IN ?", f.Occupancy)
+	}
+	return q
+}
+
+// propertyOrder turns a sort key into an ORDER BY clause with a stable tiebreaker
+func propertyOrder(sort string) (string, error) {
+	if sort == "" {
+		return "property.property_id", nil
+	}
+	dir := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		dir, sort = "DESC", sort[1:]
+	}
+	expr, ok := PropertySortFields[sort]
+	if !ok {
+		return "", ErrInvalidSort
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, property.property_id %s", expr, dir, dir), nil
+}
+
+// GetByID loads a property with its type, occupancy status, the full address
+// hierarchy and all detail tables
+func (r *propertyRepository) GetByID(ctx context.Context, id uint) (*models.Property, error) {
+	var prop models.Property
+	if err := withOccupancy(r.db.WithContext(ctx), time.Now()).
+		Select("property.*, "+occupancyStatusExpr+" AS occupancy_status").
+		Preload("PropertyType").
+		Preload("Address.Taluk.District.State.Country").
+		Preload("LandDetails").
+		Preload("TaxDetails").
+		Preload("Ownership").
+		Preload("BuildingDetails").
+		Preload("Media").
+		First(&prop, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &prop, nil
+}
+
+// Update applies a PUT (replace) or PATCH to a property, its address and the
+// five detail tables in one transaction. Missing detail rows are created.
+func (r *propertyRepository) Update(ctx context.Context, actorID, id uint, upd *dto.PropertyUpdate, replace bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prop models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prop, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
 			}
+			return err
 		}
-		propertyTypeID = propType.PropertyTypeID
-	}
-
-	// 2. Create Address hierarchy (Country -> State -> District -> Taluk -> Address)
-	var countryID, stateID, districtID, talukID, addressID uint
-
-	// Get or create Country
-	if countryName, ok := requestData["country_name"].(string); ok && countryName != "" {
-		var country models.CountryMaster
-		if err := tx.Where("country_name = ?", countryName).First(&country).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				country.CountryName = countryName
-				if err := tx.Create(&country).Error; err != nil {
-					tx.Rollback()
-					return 0, fmt.Errorf("failed to create country: %w", err)
-				}
-			} else {
-				tx.Rollback()
-				return 0, err
+
+		cols := map[string]interface{}{}
+		assign(cols, "property_name", upd.PropertyName, replace)
+		assignNullable(cols, "income", upd.Income, replace)
+		assign(cols, "original_deed", upd.OriginalDeed, replace)
+		assignNullable(cols, "unit_count", upd.UnitCount, replace)
+		switch {
+		case upd.PropertyTypeID != nil:
+			cols["property_type_id"] = *upd.PropertyTypeID
+		case upd.PropertyTypeName != nil:
+			typeID, err := getOrCreatePropertyType(tx, *upd.PropertyTypeName)
+			if err != nil {
+				return err
 			}
+			cols["property_type_id"] = typeID
 		}
-		countryID = country.CountryID
-	}
-
-	// Get or create State
-	if stateName, ok := requestData["state_name"].(string); ok && stateName != "" && countryID > 0 {
-		var state models.StateMaster
-		if err := tx.Where("state_name = ? AND country_id = ?", stateName, countryID).First(&state).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				state.StateName = stateName
-				state.CountryID = countryID
-				if err := tx.Create(&state).Error; err != nil {
-					tx.Rollback()
-					return 0, fmt.Errorf("failed to create state: %w", err)
-				}
-			} else {
-				tx.Rollback()
-				return 0, err
+		if upd.Address != nil {
+			addressID, err := updateAddress(tx, prop.AddressID, upd.Address, replace)
+			if err != nil {
+				return err
+			}
+			if addressID != prop.AddressID {
+				cols["address_id"] = addressID
 			}
 		}
-		stateID = state.StateID
-	}
-
-	// Get or create District
-	if districtName, ok := requestData["district_name"].(string); ok && districtName != "" && stateID > 0 {
-		var district models.DistrictMaster
-		if err := tx.Where("district_name = ? AND state_id = ?", districtName, stateID).First(&district).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				district.DistrictName = districtName
-				district.StateID = stateID
-				if err := tx.Create(&district).Error; err != nil {
-					tx.Rollback()
-					return 0, fmt.Errorf("failed to create district: %w", err)
-				}
-			} else {
-				tx.Rollback()
-				return 0, err
+		if len(cols) > 0 {
+			if err := tx.Model(&prop).Updates(cols).Error; err != nil {
+				return fmt.Errorf("failed to update property: %w", err)
 			}
 		}
-		districtID = district.DistrictID
-	}
-
-	// Get or create Taluk
-	if talukName, ok := requestData["taluk_name"].(string); ok && talukName != "" && districtID > 0 {
-		var taluk models.TalukMaster
-		if err := tx.Where("taluk_name = ? AND district_id = ?", talukName, districtID).First(&taluk).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				taluk.TalukName = talukName
-				taluk.DistrictID = districtID
-				if err := tx.Create(&taluk).Error; err != nil {
-					tx.Rollback()
-					return 0, fmt.Errorf("failed to create taluk: %w", err)
-				}
-			} else {
-				tx.Rollback()
-				return 0, err
+		// the value is derived from the valuation history, so a new value is
+		// recorded as a valuation; an absent one is kept even on PUT
+		if upd.Value != nil {
+			if err := recordValuation(tx, manualValuation(id, *upd.Value, actorID)); err != nil {
+				return err
 			}
 		}
-		talukID = taluk.TalukID
+
+		details := []struct {
+			model interface{}
+			cols  map[string]interface{}
+		}{
+			{&models.PropertyLandDetails{}, landColumns(upd.Land, replace)},
+			{&models.PropertyTaxDetails{}, taxColumns(upd.Tax, replace)},
+			{&models.PropertyOwnershipDetails{}, ownershipColumns(upd.Ownership, replace)},
+			{&models.PropertyBuildingDetails{}, buildingColumns(upd.Building, replace)},
+			{&models.PropertyMedia{}, mediaColumns(upd.Media, replace)},
+		}
+		for _, d := range details {
+			if err := upsertDetails(tx, d.model, id, d.cols); err != nil {
+				return err
+			}
+		}
+		if upd.Land != nil || replace {
+			// a PATCH may change karab or the extent alone, so the rule the
+			// service checks on create is checked again on the merged row
+			var invalid int64
+			if err := tx.Model(&models.PropertyLandDetails{}).
+				Where("property_id = ? AND karab IS NOT NULL AND (acre IS NOT NULL OR gunte IS NOT NULL)", id).
+				Where("karab > COALESCE(acre, 0) * 40 + COALESCE(gunte, 0)").
+				Count(&invalid).Error; err != nil {
+				return err
+			}
+			if invalid > 0 {
+				return ErrKarabExceedsExtent
+			}
+			_, err := refreshLandArea(tx.Where("property_id = ?", id))
+			return err
+		}
+		return nil
+	})
+}
+
+// Delete soft-deletes a property. It is refused while an agreement that has
+// not ended yet, or any asset, still references the property.
+func (r *propertyRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prop models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prop, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		agreements, assets, err := countReferences(tx, id, true)
+		if err != nil {
+			return err
+		}
+		if agreements > 0 || assets > 0 {
+			return ErrPropertyInUse
+		}
+		return tx.Delete(&prop).Error
+	})
+}
+
+// Restore undoes a soft delete
+func (r *propertyRepository) Restore(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Unscoped().Model(&models.Property{}).
+		Where("property_id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
 	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
 
-	// Create Address
-	if talukID > 0 {
+// Purge permanently removes a soft-deleted property with its detail rows and
+// address. Database foreign keys are not created by AutoMigrate here, so the
+// children are deleted explicitly. Any agreement or asset, past or present,
+// blocks the purge because it would be left pointing at nothing.
+func (r *propertyRepository) Purge(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prop models.Property
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&prop, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if !prop.DeletedAt.Valid {
+			return ErrPropertyNotDeleted
+		}
+		agreements, assets, err := countReferences(tx, id, false)
+		if err != nil {
+			return err
+		}
+		if agreements > 0 || assets > 0 {
+			return ErrPropertyInUse
+		}
+
+		for _, child := range []interface{}{
+			&models.PropertyLandDetails{},
+			&models.PropertyTaxDetails{},
+			&models.PropertyOwnershipDetails{},
+			&models.PropertyBuildingDetails{},
+			&models.PropertyMedia{},
+			&models.PropertyValuation{},
+		} {
+			if err := tx.Where("property_id = ?", id).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Delete(&prop).Error; err != nil {
+			return err
+		}
+
+		if prop.AddressID == 0 {
+			return nil
+		}
+		var shared int64
+		if err := tx.Unscoped().Model(&models.Property{}).Where("address_id = ?", prop.AddressID).Count(&shared).Error; err != nil {
+			return err
+		}
+		if shared > 0 {
+			return nil
+		}
+		return tx.Delete(&models.Address{}, prop.AddressID).Error
+	})
+}
+
+// References counts the agreements and assets pointing at a property; with
+// activeOnly set, agreements that have already ended are not counted
+func (r *propertyRepository) References(ctx context.Context, id uint, activeOnly bool) (int64, int64, error) {
+	return countReferences(r.db.WithContext(ctx), id, activeOnly)
+}
+
+func countReferences(tx *gorm.DB, propertyID uint, activeOnly bool) (int64, int64, error) {
+	var agreements, assets int64
+	q := tx.Model(&models.Agreement{}).Where("property_id = ?", propertyID)
+	if activeOnly {
+		// end dates are inclusive and stored at midnight, so compare with today's date
+		q = q.Where("end_date >= ?", utils.DateOf(time.Now()))
+	}
+	if err := q.Count(&agreements).Error; err != nil {
+		return 0, 0, err
+	}
+	// assets carry no end-of-life state, so every asset counts as active
+	if err := tx.Model(&models.Asset{}).Where("property_id = ?", propertyID).Count(&assets).Error; err != nil {
+		return 0, 0, err
+	}
+	return agreements, assets, nil
+}
+
+// updateAddress updates the property's address row, or creates one when the
+// property has none yet, and returns the address ID to store on the property
+func updateAddress(tx *gorm.DB, addressID uint, a *dto.AddressUpdate, replace bool) (uint, error) {
+	var talukID uint
+	if a.HasFullLocation() {
+		var err error
+		if talukID, err = resolveTaluk(tx, *a.CountryName, *a.StateName, *a.DistrictName, *a.TalukName); err != nil {
+			return 0, err
+		}
+	}
+
+	if addressID == 0 {
+		if talukID == 0 {
+			return 0, ErrAddressLocationRequired
+		}
 		address := models.Address{
 			TalukID:        talukID,
-			Hobli:          getStringValue(requestData, "hobli"),
-			Village:        getStringValue(requestData, "village"),
-			StreetAddress:  getStringValue(requestData, "street_address"),
-			Pincode:        getStringValue(requestData, "pincode"),
-			LatCoordinate:  getStringValue(requestData, "lat_coordinate"),
-			LongCoordinate: getStringValue(requestData, "long_coordinate"),
+			Hobli:          deref(a.Hobli),
+			Village:        deref(a.Village),
+			StreetAddress:  deref(a.StreetAddress),
+			Pincode:        deref(a.Pincode),
+			LatCoordinate:  deref(a.LatCoordinate),
+			LongCoordinate: deref(a.LongCoordinate),
 		}
 		if err := tx.Create(&address).Error; err != nil {
-			tx.Rollback()
 			return 0, fmt.Errorf("failed to create address: %w", err)
 		}
-		addressID = address.AddressID
+		return address.AddressID, nil
 	}
 
-	// 3. Create Property
-	property := models.Property{
-		PropertyName:   getStringValue(requestData, "property_name"),
-		PropertyTypeID: propertyTypeID,
-		AddressID:      addressID,
-		UserID:         uint(getFloatValue(requestData, "user_id")),
-		Value:          getStringValue(requestData, "value"),
-		Income:         getStringValue(requestData, "income"),
-		OriginalDeed:   getStringValue(requestData, "original_deed"),
+	cols := map[string]interface{}{}
+	if talukID > 0 {
+		cols["taluk_id"] = talukID
 	}
-
-	if err := tx.Create(&property).Error; err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("failed to create property: %w", err)
+	assign(cols, "hobli", a.Hobli, replace)
+	assign(cols, "village", a.Village, replace)
+	assign(cols, "street_address", a.StreetAddress, replace)
+	assign(cols, "pincode", a.Pincode, replace)
+	assign(cols, "lat_coordinate", a.LatCoordinate, replace)
+	assign(cols, "long_coordinate", a.LongCoordinate, replace)
+	if len(cols) > 0 {
+		if err := tx.Model(&models.Address{}).Where("address_id = ?", addressID).Updates(cols).Error; err != nil {
+			return 0, fmt.Errorf("failed to update address: %w", err)
+		}
 	}
+	return addressID, nil
+}
 
-	// 4. Create PropertyLandDetails
-	landDetails := models.PropertyLandDetails{
-		PropertyID: property.PropertyID,
-		Rtc:        getStringValue(requestData, "rtc"),
-		Ec:         getStringValue(requestData, "ec"),
-		SyNo:       getStringValue(requestData, "syno"),
-		KhataNo:    getStringValue(requestData, "khatano"),
-		MrNo:       getStringValue(requestData, "mrno"),
-		Acre:       getStringValue(requestData, "acre"),
-		Gunte:      getStringValue(requestData, "gunte"),
-		Karab:      getStringValue(requestData, "karab"),
-		Converted:  getStringValue(requestData, "converted"),
-		Purpose:    getStringValue(requestData, "purpose"),
-		KhuskiTari: getStringValue(requestData, "khuskitari"),
+// upsertDetails writes the given columns to a 1–1 detail table, inserting the
+// row when the property does not have one yet; nil or empty cols is a no-op
+func upsertDetails(tx *gorm.DB, model interface{}, propertyID uint, cols map[string]interface{}) error {
+	if len(cols) == 0 {
+		return nil
 	}
-	if err := tx.Create(&landDetails).Error; err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("failed to create land details: %w", err)
+	updated := make([]string, 0, len(cols))
+	for col := range cols {
+		updated = append(updated, col)
 	}
+	sort.Strings(updated)
+	cols["property_id"] = propertyID
+	return tx.Model(model).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "property_id"}},
+			DoUpdates: clause.AssignmentColumns(updated),
+		}).
+		Create(cols).Error
+}
 
-	// 5. Create PropertyTaxDetails
-	taxDetails := models.PropertyTaxDetails{
-		PropertyID:  property.PropertyID,
-		ReceiptNo:   getStringValue(requestData, "receipt_no"),
-		PrevAmount:  getFloatValue(requestData, "prev_amount"),
-		CurrAmount:  getFloatValue(requestData, "curr_amount"),
-		ReceiptLink: getStringValue(requestData, "receipt_link"),
-	}
-	if err := tx.Create(&taxDetails).Error; err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("failed to create tax details: %w", err)
-	}
+// The *Columns helpers map an update section to column values. With replace
+// set, an absent section or field clears the stored value.
 
-	// 6. Create PropertyOwnershipDetails
-	ownershipDetails := models.PropertyOwnershipDetails{
-		PropertyID:          property.PropertyID,
-		ReceivedFrom:        getStringValue(requestData, "received_from"),
-		AcquisitionType:     getStringValue(requestData, "acquisition_type"),
-		RegistrationDetails: getStringValue(requestData, "registration_details"),
-		Title:               getStringValue(requestData, "title"),
-		Incharge:            getStringValue(requestData, "incharge"),
-		PhoneNumber:         getStringValue(requestData, "phone_number"),
+func landColumns(l *dto.LandDetailsUpdate, replace bool) map[string]interface{} {
+	if l == nil {
+		if !replace {
+			return nil
+		}
+		l = &dto.LandDetailsUpdate{}
 	}
-	if err := tx.Create(&ownershipDetails).Error; err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("failed to create ownership details: %w", err)
+	cols := map[string]interface{}{}
+	assign(cols, "rtc", l.Rtc, replace)
+	assign(cols, "ec", l.Ec, replace)
+	assign(cols, "sy_no", l.SyNo, replace)
+	assign(cols, "khata_no", l.KhataNo, replace)
+	assign(cols, "mr_no", l.MrNo, replace)
+	assignNullable(cols, "acre", l.Acre, replace)
+	assignNullable(cols, "gunte", l.Gunte, replace)
+	assignNullable(cols, "karab", l.Karab, replace)
+	assign(cols, "converted", l.Converted, replace)
+	assign(cols, "purpose", l.Purpose, replace)
+	assign(cols, "khuski_tari", l.KhuskiTari, replace)
+	return cols
+}
+
+func taxColumns(t *dto.TaxDetailsUpdate, replace bool) map[string]interface{} {
+	if t == nil {
+		if !replace {
+			return nil
+		}
+		t = &dto.TaxDetailsUpdate{}
 	}
+	cols := map[string]interface{}{}
+	assign(cols, "tax_paid", t.TaxPaid, replace)
+	assign(cols, "receipt_no", t.ReceiptNo, replace)
+	assign(cols, "tax_amount_paid_previous_year", t.PrevAmount, replace)
+	assign(cols, "tax_amount_paid_current_year", t.CurrAmount, replace)
+	assign(cols, "receipt_photo_link", t.ReceiptLink, replace)
+	return cols
+}
 
-	// 7. Create PropertyBuildingDetails
-	buildingDetails := models.PropertyBuildingDetails{
-		PropertyID:         property.PropertyID,
-		PlotSize:           getStringValue(requestData, "plot_size"),
-		BuiltUpArea:        getStringValue(requestData, "built_up_area"),
-		YearOfConstruction: getStringValue(requestData, "year_of_construction"),
-		ApplicationNo:      getStringValue(requestData, "application_no"),
+func ownershipColumns(o *dto.OwnershipUpdate, replace bool) map[string]interface{} {
+	if o == nil {
+		if !replace {
+			return nil
+		}
+		o = &dto.OwnershipUpdate{}
 	}
-	if err := tx.Create(&buildingDetails).Error; err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("failed to create building details: %w", err)
+	cols := map[string]interface{}{}
+	assign(cols, "received_from", o.ReceivedFrom, replace)
+	assign(cols, "acquisition_type", o.AcquisitionType, replace)
+	assign(cols, "registration_details", o.RegistrationDetails, replace)
+	assign(cols, "title", o.Title, replace)
+	assign(cols, "incharge", o.Incharge, replace)
+	assign(cols, "phone_number", o.PhoneNumber, replace)
+	return cols
+}
+
+func buildingColumns(b *dto.BuildingDetailsUpdate, replace bool) map[string]interface{} {
+	if b == nil {
+		if !replace {
+			return nil
+		}
+		b = &dto.BuildingDetailsUpdate{}
 	}
+	cols := map[string]interface{}{}
+	assignNullable(cols, "plot_size", b.PlotSize, replace)
+	assignNullable(cols, "built_up_area", b.BuiltUpArea, replace)
+	assign(cols, "year_of_construction", b.YearOfConstruction, replace)
+	assign(cols, "application_no", b.ApplicationNo, replace)
+	return cols
+}
 
-	// 8. Create PropertyMedia
-	media := models.PropertyMedia{
-		PropertyID:      property.PropertyID,
-		ScannedDeedLink: getStringValue(requestData, "scanned_deed_link"),
-		PhotoLink:       getStringValue(requestData, "photo_link"),
-		Remarks:         getStringValue(requestData, "remarks"),
+func mediaColumns(m *dto.MediaUpdate, replace bool) map[string]interface{} {
+	if m == nil {
+		if !replace {
+			return nil
+		}
+		m = &dto.MediaUpdate{}
 	}
-	if err := tx.Create(&media).Error; err != nil {
-		tx.Rollback()
-		return 0, fmt.Errorf("failed to create media: %w", err)
+	cols := map[string]interface{}{}
+	assign(cols, "scanned_deed_link", m.ScannedDeedLink, replace)
+	assign(cols, "photo_link", m.PhotoLink, replace)
+	assign(cols, "remarks", m.Remarks, replace)
+	return cols
+}
+
+// landExtentSqm is the land extent in square metres
+var landExtentSqm = fmt.Sprintf("(COALESCE(acre, 0) * %v + COALESCE(gunte, 0) * %v)",
+	utils.SquareMetresPerAcre, utils.SquareMetresPerGunta)
+
+// refreshLandArea recomputes the canonical total and usable (total minus
+// karab) areas of the land detail rows selected by q. Both stay NULL while
+// neither acre nor gunte is known.
+func refreshLandArea(q *gorm.DB) (int64, error) {
+	unknown := "acre IS NULL AND gunte IS NULL"
+	res := q.Model(&models.PropertyLandDetails{}).Updates(map[string]interface{}{
+		"total_area_sqm": gorm.Expr(fmt.Sprintf("CASE WHEN %s THEN NULL ELSE %s END", unknown, landExtentSqm)),
+		"usable_area_sqm": gorm.Expr(fmt.Sprintf("CASE WHEN %s THEN NULL ELSE GREATEST(%s - COALESCE(karab, 0) * %v, 0) END",
+			unknown, landExtentSqm, utils.SquareMetresPerGunta)),
+	})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to compute land area: %w", res.Error)
 	}
+	return res.RowsAffected, nil
+}
+
+// BackfillLandAreas computes the canonical areas of land detail rows that
+// have an extent but no canonical area yet
+func BackfillLandAreas(db *gorm.DB) (int64, error) {
+	return refreshLandArea(db.Where("total_area_sqm IS NULL AND (acre IS NOT NULL OR gunte IS NOT NULL)"))
+}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+// assign sets cols[col] from v; a nil v clears the column only when replacing
+func assign[T any](cols map[string]interface{}, col string, v *T, replace bool) {
+	if v != nil {
+		cols[col] = *v
+	} else if replace {
+		var zero T
+		cols[col] = zero
 	}
+}
 
-	return int64(property.PropertyID), nil
+// assignNullable is assign for nullable columns: replacing with a nil v stores NULL
+func assignNullable[T any](cols map[string]interface{}, col string, v *T, replace bool) {
+	if v != nil {
+		cols[col] = *v
+	} else if replace {
+		cols[col] = nil
+	}
 }
 
-func (r *propertyRepository) ListByType(ctx context.Context, propertyType string) ([]models.Property, error) {
-	var props []models.Property
-	if err := r.db.WithContext(ctx).
-		Preload("Address").
-		Preload("PropertyType").
-		Joins("JOIN property_type_master ON property_type_master.property_type_id = property.property_type_id").
-		Where("property_type_master.property_type_name = ?", propertyType).
-		Find(&props).Error; err != nil {
-		return nil, err
+func deref(s *string) string {
+	if s == nil {
+		return ""
 	}
-	return props, nil
+	return *s
 }
 
-// Helper functions
-func getStringValue(data map[string]interface{}, key string) string {
-	if val, ok := data[key]; ok {
-		if str, ok := val.(string); ok {
-			return str
-		}
+func getOrCreatePropertyType(tx *gorm.DB, name string) (uint, error) {
+	var propType models.PropertyTypeMaster
+	if err := tx.Where(models.PropertyTypeMaster{PropertyTypeName: name}).FirstOrCreate(&propType).Error; err != nil {
+		return 0, fmt.Errorf("failed to create property type: %w", err)
 	}
-	return ""
+	return propType.PropertyTypeID, nil
 }
 
-func getFloatValue(data map[string]interface{}, key string) float64 {
-	if val, ok := data[key]; ok {
-		if f, ok := val.(float64); ok {
-			return f
-		}
-		if str, ok := val.(string); ok {
-			if f, err := strconv.ParseFloat(str, 64); err == nil {
-				return f
-			}
-		}
+// resolveTaluk gets or creates the Country -> State -> District -> Taluk chain.
+// Each level is only resolved when its name and parent are present; the
+// result is 0 when the chain is incomplete.
+func resolveTaluk(tx *gorm.DB, countryName, stateName, districtName, talukName string) (uint, error) {
+	if countryName == "" {
+		return 0, nil
+	}
+	var country models.CountryMaster
+	if err := tx.Where(models.CountryMaster{CountryName: countryName}).FirstOrCreate(&country).Error; err != nil {
+		return 0, fmt.Errorf("failed to create country: %w", err)
+	}
+
+	if stateName == "" {
+		return 0, nil
+	}
+	var state models.StateMaster
+	if err := tx.Where(models.StateMaster{StateName: stateName, CountryID: country.CountryID}).FirstOrCreate(&state).Error; err != nil {
+		return 0, fmt.Errorf("failed to create state: %w", err)
+	}
+
+	if districtName == "" {
+		return 0, nil
+	}
+	var district models.DistrictMaster
+	if err := tx.Where(models.DistrictMaster{DistrictName: districtName, StateID: state.StateID}).FirstOrCreate(&district).Error; err != nil {
+		return 0, fmt.Errorf("failed to create district: %w", err)
+	}
+
+	if talukName == "" {
+		return 0, nil
+	}
+	var taluk models.TalukMaster
+	if err := tx.Where(models.TalukMaster{TalukName: talukName, DistrictID: district.DistrictID}).FirstOrCreate(&taluk).Error; err != nil {
+		return 0, fmt.Errorf("failed to create taluk: %w", err)
 	}
-	return 0
+	return taluk.TalukID, nil
 }
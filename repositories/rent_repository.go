@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"property-backend/dto"
+	"property-backend/models"
+	"property-backend/utils"
+)
+
+// RentRepository defines rent schedule and payment data access methods
+type RentRepository interface {
+	Agreement(ctx context.Context, id uint) (*models.Agreement, error)
+	Schedule(ctx context.Context, agreementID uint) ([]models.RentScheduleEntry, error)
+	Payments(ctx context.Context, agreementID uint) ([]models.RentPayment, error)
+	AddPayment(ctx context.Context, p *models.RentPayment) error
+	Overdue(ctx context.Context, asOf time.Time, page Page) ([]dto.OverdueRent, PageInfo, error)
+}
+
+type rentRepository struct {
+	db *gorm.DB
+}
+
+// NewRentRepository constructs a RentRepository
+func NewRentRepository(db *gorm.DB) RentRepository {
+	return &rentRepository{db: db}
+}
+
+func (r *rentRepository) Agreement(ctx context.Context, id uint) (*models.Agreement, error) {
+	var a models.Agreement
+	if err := r.db.WithContext(ctx).First(&a, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Schedule returns an agreement's billing periods in date order
+func (r *rentRepository) Schedule(ctx context.Context, agreementID uint) ([]models.RentScheduleEntry, error) {
+	var entries []models.RentScheduleEntry
+	err := r.db.WithContext(ctx).
+		Where("agreement_id = ?", agreementID).
+		Order("due_date, schedule_id").
+		Find(&entries).Error
+	return entries, err
+}
+
+// Payments returns the payments received against an agreement in date order
+func (r *rentRepository) Payments(ctx context.Context, agreementID uint) ([]models.RentPayment, error) {
+	var payments []models.RentPayment
+	err := r.db.WithContext(ctx).
+		Where("agreement_id = ?", agreementID).
+		Order("paid_on, payment_id").
+		Find(&payments).Error
+	return payments, err
+}
+
+// AddPayment records a payment against an existing agreement
+func (r *rentRepository) AddPayment(ctx context.Context, p *models.RentPayment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var a models.Agreement
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("agreement_id").First(&a, p.AgreementID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		return tx.Create(p).Error
+	})
+}
+
+// overdueRent groups, per agreement, the rent that fell due before @as_of and
+// is not covered by payments. Payments settle the oldest rent first, so a
+// period is unpaid once the rent due up to and including it exceeds
+// everything paid.
+const overdueRent = `
+	WITH paid AS (
+		SELECT agreement_id, SUM(amount) AS paid
+		FROM rent_payments
+		GROUP BY agreement_id
+	), due AS (
+		SELECT s.agreement_id, s.due_date, s.amount,
+			SUM(s.amount) OVER (PARTITION BY s.agreement_id ORDER BY s.due_date, s.schedule_id) AS cumulative
+		FROM rent_schedule s
+		WHERE s.due_date < @as_of
+	)
+	SELECT a.agreement_id, a.property_id, p.property_name, a.tenant_name, a.contact_no, a.unit_no,
+		SUM(LEAST(due.amount, due.cumulative - COALESCE(paid.paid, 0))) AS overdue_amount,
+		COUNT(*) AS overdue_periods,
+		MIN(due.due_date) AS oldest_due_date
+	FROM due
+	JOIN agreement a ON a.agreement_id = due.agreement_id
+	JOIN property p ON p.property_id = a.property_id AND p.deleted_at IS NULL
+	LEFT JOIN paid ON paid.agreement_id = due.agreement_id
+	WHERE due.cumulative > COALESCE(paid.paid, 0)
+	GROUP BY a.agreement_id, a.property_id, p.property_name, a.tenant_name, a.contact_no, a.unit_no`
+
+// Overdue lists agreements with rent unpaid past its due date, largest
+// arrears first. The order is not by primary key, so only offset pagination
+// is supported.
+func (r *rentRepository) Overdue(ctx context.Context, asOf time.Time, page Page) ([]dto.OverdueRent, PageInfo, error) {
+	if page.AfterID > 0 {
+		return nil, PageInfo{}, ErrCursorUnsupported
+	}
+	db := r.db.WithContext(ctx)
+	day := asOf.Format(time.DateOnly)
+	var total int64
+	if err := db.Raw(`SELECT COUNT(*) FROM (`+overdueRent+`) overdue`,
+		map[string]interface{}{"as_of": day}).Scan(&total).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	rows := []dto.OverdueRent{}
+	if err := db.Raw(overdueRent+` ORDER BY overdue_amount DESC, a.agreement_id LIMIT @limit OFFSET @offset`,
+		map[string]interface{}{"as_of": day, "limit": page.limit() + 1, "offset": page.Offset}).
+		Scan(&rows).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	rows, info := finishPage(rows, page, total, func(o *dto.OverdueRent) uint { return o.AgreementID })
+	info.NextCursor = ""
+	return rows, info, nil
+}
+
+// createRentSchedule stores the billing periods of a new agreement
+func createRentSchedule(tx *gorm.DB, a *models.Agreement) error {
+	entries := buildRentSchedule(a)
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return fmt.Errorf("failed to create rent schedule: %w", err)
+	}
+	return nil
+}
+
+// buildRentSchedule splits an agreement into billing periods of its rent
+// frequency, anchored on the start date. Each escalation starts a new run of
+// periods at the escalated rent, so no period spans two rents. Rent for a
+// period is the monthly rent times the months in it; a period cut short by
+// the next escalation or the end date is charged for the days it covers.
+func buildRentSchedule(a *models.Agreement) []models.RentScheduleEntry {
+	months, ok := models.RentFrequencyMonths[a.RentFrequency]
+	if !ok {
+		months = 1
+	}
+	end := utils.DateOf(a.EndDate)
+
+	var entries []models.RentScheduleEntry
+	for step := 0; ; {
+		from, rent := a.EscalationDate(step), a.RentAfter(step)
+		// escalations that leave the rent unchanged, such as those past the
+		// cap, do not break the run
+		next := step + 1
+		for a.Escalates() && !a.EscalationDate(next).After(end) && a.RentAfter(next) == rent {
+			next++
+		}
+		if !a.Escalates() || a.EscalationDate(next).After(end) {
+			return appendRentPeriods(entries, a.AgreementID, from, end, months, rent)
+		}
+		entries = appendRentPeriods(entries, a.AgreementID, from, a.EscalationDate(next).AddDate(0, 0, -1), months, rent)
+		step = next
+	}
+}
+
+// appendRentPeriods adds the billing periods from one day to another at a
+// single monthly rent
+func appendRentPeriods(entries []models.RentScheduleEntry, agreementID uint, from, to time.Time, months int, rent float64) []models.RentScheduleEntry {
+	full := models.MoneyFromFloat(rent * float64(months))
+	for i := 0; ; i++ {
+		periodStart := utils.AddMonths(from, i*months)
+		if periodStart.After(to) {
+			break
+		}
+		next := utils.AddMonths(from, (i+1)*months)
+		periodEnd, amount := next.AddDate(0, 0, -1), full
+		if periodEnd.After(to) {
+			share := float64(daysBetween(periodStart, to)+1) / float64(daysBetween(periodStart, next))
+			periodEnd, amount = to, models.Money(math.Round(float64(full)*share))
+		}
+		entries = append(entries, models.RentScheduleEntry{
+			AgreementID: agreementID,
+			PeriodStart: periodStart,
+			PeriodEnd:   periodEnd,
+			DueDate:     periodStart,
+			Amount:      amount,
+		})
+	}
+	return entries
+}
+
+// BackfillRentSchedules builds the schedule of every agreement that has valid
+// dates but no schedule yet, returning how many agreements were scheduled
+func BackfillRentSchedules(db *gorm.DB) (int64, error) {
+	var agreements []models.Agreement
+	if err := db.
+		Where("end_date > start_date").
+		Where("NOT EXISTS (SELECT 1 FROM rent_schedule s WHERE s.agreement_id = agreement.agreement_id)").
+		Find(&agreements).Error; err != nil {
+		return 0, err
+	}
+	for i := range agreements {
+		if err := createRentSchedule(db, &agreements[i]); err != nil {
+			return int64(i), err
+		}
+	}
+	return int64(len(agreements)), nil
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
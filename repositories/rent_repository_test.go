@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"property-backend/models"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestBuildRentSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		agreement models.Agreement
+		periods   int
+		full      models.Money // amount of every period but the last
+		lastStart time.Time
+		lastEnd   time.Time
+		last      models.Money
+	}{
+		{
+			name:      "monthly whole year",
+			agreement: models.Agreement{Rent: 10000, RentFrequency: models.RentMonthly, StartDate: day(2026, 1, 1), EndDate: day(2026, 12, 31)},
+			periods:   12, full: 1000000,
+			lastStart: day(2026, 12, 1), lastEnd: day(2026, 12, 31), last: 1000000,
+		},
+		{
+			name:      "frequency defaults to monthly",
+			agreement: models.Agreement{Rent: 10000, StartDate: day(2026, 1, 1), EndDate: day(2026, 3, 31)},
+			periods:   3, full: 1000000,
+			lastStart: day(2026, 3, 1), lastEnd: day(2026, 3, 31), last: 1000000,
+		},
+		{
+			name:      "quarterly whole year",
+			agreement: models.Agreement{Rent: 10000, RentFrequency: models.RentQuarterly, StartDate: day(2026, 1, 1), EndDate: day(2026, 12, 31)},
+			periods:   4, full: 3000000,
+			lastStart: day(2026, 10, 1), lastEnd: day(2026, 12, 31), last: 3000000,
+		},
+		{
+			// 17 of the 31 days from 15 March to 14 April
+			name:      "monthly with a short last period",
+			agreement: models.Agreement{Rent: 10000, RentFrequency: models.RentMonthly, StartDate: day(2026, 1, 15), EndDate: day(2026, 3, 31)},
+			periods:   3, full: 1000000,
+			lastStart: day(2026, 3, 15), lastEnd: day(2026, 3, 31), last: 548387,
+		},
+		{
+			// 62 of the 92 days from 1 July to 30 September
+			name:      "quarterly with a short last period",
+			agreement: models.Agreement{Rent: 10000, RentFrequency: models.RentQuarterly, StartDate: day(2026, 1, 1), EndDate: day(2026, 8, 31)},
+			periods:   3, full: 3000000,
+			lastStart: day(2026, 7, 1), lastEnd: day(2026, 8, 31), last: 2021739,
+		},
+		{
+			name:      "month end start keeps to month ends",
+			agreement: models.Agreement{Rent: 9000, RentFrequency: models.RentMonthly, StartDate: day(2026, 1, 31), EndDate: day(2026, 4, 29)},
+			periods:   3, full: 900000,
+			lastStart: day(2026, 3, 31), lastEnd: day(2026, 4, 29), last: 900000,
+		},
+		{
+			name:      "single day",
+			agreement: models.Agreement{Rent: 3100, RentFrequency: models.RentMonthly, StartDate: day(2026, 1, 1), EndDate: day(2026, 1, 1)},
+			periods:   1,
+			lastStart: day(2026, 1, 1), lastEnd: day(2026, 1, 1), last: 10000,
+		},
+	} {
+		entries := buildRentSchedule(&tc.agreement)
+		if len(entries) != tc.periods {
+			t.Errorf("%s: %d periods, want %d", tc.name, len(entries), tc.periods)
+			continue
+		}
+		for i, e := range entries[:len(entries)-1] {
+			if e.Amount != tc.full {
+				t.Errorf("%s: period %d charged %s, want %s", tc.name, i, e.Amount, tc.full)
+			}
+			if next := entries[i+1].PeriodStart; !e.PeriodEnd.AddDate(0, 0, 1).Equal(next) {
+				t.Errorf("%s: period %d ends %s but the next starts %s", tc.name, i, e.PeriodEnd.Format(time.DateOnly), next.Format(time.DateOnly))
+			}
+		}
+		last := entries[len(entries)-1]
+		if !last.PeriodStart.Equal(tc.lastStart) || !last.PeriodEnd.Equal(tc.lastEnd) || !last.DueDate.Equal(tc.lastStart) {
+			t.Errorf("%s: last period %s to %s due %s, want %s to %s", tc.name,
+				last.PeriodStart.Format(time.DateOnly), last.PeriodEnd.Format(time.DateOnly), last.DueDate.Format(time.DateOnly),
+				tc.lastStart.Format(time.DateOnly), tc.lastEnd.Format(time.DateOnly))
+		}
+		if last.Amount != tc.last {
+			t.Errorf("%s: last period charged %s, want %s", tc.name, last.Amount, tc.last)
+		}
+	}
+}
+
+func TestBuildRentScheduleEscalation(t *testing.T) {
+	limit := 11500.0
+	a := models.Agreement{
+		Rent: 10000, RentFrequency: models.RentQuarterly,
+		StartDate: day(2026, 1, 1), EndDate: day(2028, 12, 31),
+		EscalationType: models.EscalationPercentage, EscalationValue: 10, EscalationIntervalMonths: 12,
+		EscalationCap: &limit,
+	}
+	entries := buildRentSchedule(&a)
+	// 10000 the first year, 11000 the second and the 11500 cap the third
+	want := []models.Money{3000000, 3000000, 3000000, 3000000, 3300000, 3300000, 3300000, 3300000, 3450000, 3450000, 3450000, 3450000}
+	if len(entries) != len(want) {
+		t.Fatalf("%d periods, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Amount != want[i] {
+			t.Errorf("period %d (%s) charged %s, want %s", i, e.PeriodStart.Format(time.DateOnly), e.Amount, want[i])
+		}
+	}
+}
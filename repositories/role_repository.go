@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"property-backend/models"
+)
+
+// ErrLastRole is returned when revoking a role would leave a user without any role
+var ErrLastRole = errors.New("user must keep at least one role")
+
+// ErrLastAdmin is returned when revoking the admin role would leave no active admin
+var ErrLastAdmin = errors.New("cannot revoke the admin role from the last active admin")
+
+// RoleRepository defines role and user-role data access methods
+type RoleRepository interface {
+	ListAll(ctx context.Context) ([]models.RolesMaster, error)
+	GetByID(ctx context.Context, id uint) (*models.RolesMaster, error)
+	GetByName(ctx context.Context, name string) (*models.RolesMaster, error)
+	ListForUser(ctx context.Context, userID uint) ([]models.RolesMaster, error)
+	Grant(ctx context.Context, userID, roleID uint) error
+	Revoke(ctx context.Context, userID, roleID uint) error
+	SetRequire2FA(ctx context.Context, roleID uint, require bool) error
+	UserRequires2FA(ctx context.Context, userID uint) (bool, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository constructs a RoleRepository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) ListAll(ctx context.Context) ([]models.RolesMaster, error) {
+	var roles []models.RolesMaster
+	if err := r.db.WithContext(ctx).Order("roles_master_id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) GetByID(ctx context.Context, id uint) (*models.RolesMaster, error) {
+	var role models.RolesMaster
+	if err := r.db.WithContext(ctx).First(&role, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) GetByName(ctx context.Context, name string) (*models.RolesMaster, error) {
+	var role models.RolesMaster
+	if err := r.db.WithContext(ctx).Where("role = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) ListForUser(ctx context.Context, userID uint) ([]models.RolesMaster, error) {
+	var roles []models.RolesMaster
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN user_roles ON user_roles.role_id = roles_master.roles_master_id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles_master.roles_master_id").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// Grant adds a role to a user; granting a role the user already holds is a no-op
+func (r *roleRepository) Grant(ctx context.Context, userID, roleID uint) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.UserRoles{UserID: userID, RoleID: roleID}).Error
+}
+
+// Revoke removes a role from a user. When the revoked role is the user's
+// primary role, user.role_id is moved to one of the remaining roles so that
+// user.role_id always appears in user_roles. The admin role is never revoked
+// from the last active admin.
+func (r *roleRepository) Revoke(ctx context.Context, userID, roleID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// locking the role row serializes concurrent revocations of the same
+		// role, so two admins cannot remove each other at once
+		var role models.RolesMaster
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&role, roleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var remaining []uint
+		if err := tx.Model(&models.UserRoles{}).
+			Where("user_id = ? AND role_id <> ?", userID, roleID).
+			Order("role_id").
+			Pluck("role_id", &remaining).Error; err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			return ErrLastRole
+		}
+
+		res := tx.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&models.UserRoles{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		if role.Role == models.RoleAdmin && user.IsActive {
+			var otherAdmins int64
+			if err := tx.Model(&models.UserRoles{}).
+				Joins(`JOIN "user" ON "user".user_id = user_roles.user_id`).
+				Where(`user_roles.role_id = ? AND "user".is_active`, roleID).
+				Count(&otherAdmins).Error; err != nil {
+				return err
+			}
+			if otherAdmins == 0 {
+				// returning an error rolls the delete back
+				return ErrLastAdmin
+			}
+		}
+
+		if user.RoleID == roleID {
+			return tx.Model(&user).Update("role_id", remaining[0]).Error
+		}
+		return nil
+	})
+}
+
+func (r *roleRepository) SetRequire2FA(ctx context.Context, roleID uint, require bool) error {
+	res := r.db.WithContext(ctx).Model(&models.RolesMaster{}).
+		Where("roles_master_id = ?", roleID).
+		Update("require_2fa", require)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UserRequires2FA reports whether any role held by the user requires two-factor authentication
+func (r *roleRepository) UserRequires2FA(ctx context.Context, userID uint) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&models.RolesMaster{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles_master.roles_master_id").
+		Where("user_roles.user_id = ? AND roles_master.require_2fa = ?", userID, true).
+		Count(&n).Error
+	return n > 0, err
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"property-backend/models"
+)
+
+// SecurityRepository defines data access for login attempts and audit records
+type SecurityRepository interface {
+	RecordLoginAttempt(ctx context.Context, a *models.LoginAttempt) error
+	CountFailedLoginsByIP(ctx context.Context, ip string, since time.Time) (int64, error)
+	RecordAudit(ctx context.Context, entry *models.AuditLog) error
+}
+
+type securityRepository struct {
+	db *gorm.DB
+}
+
+// NewSecurityRepository constructs a SecurityRepository
+func NewSecurityRepository(db *gorm.DB) SecurityRepository {
+	return &securityRepository{db: db}
+}
+
+func (r *securityRepository) RecordLoginAttempt(ctx context.Context, a *models.LoginAttempt) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+func (r *securityRepository) CountFailedLoginsByIP(ctx context.Context, ip string, since time.Time) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&models.LoginAttempt{}).
+		Where("ip_address = ? AND success = ? AND created_at >= ?", ip, false, since).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *securityRepository) RecordAudit(ctx context.Context, entry *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
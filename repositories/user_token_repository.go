@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"property-backend/models"
+)
+
+// ErrTokenInvalid is returned when a token does not exist, has expired or was already used
+var ErrTokenInvalid = errors.New("token is invalid or expired")
+
+// UserTokenRepository defines data access for single-use user tokens
+type UserTokenRepository interface {
+	Create(ctx context.Context, t *models.UserToken) error
+	ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (uint, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (uint, error)
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewUserTokenRepository constructs a UserTokenRepository
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+// Create stores a new token and invalidates any earlier unused token the
+// user holds for the same purpose, so only the latest link works
+func (r *userTokenRepository) Create(ctx context.Context, t *models.UserToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", t.UserID, t.Purpose).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(t).Error
+	})
+}
+
+// ResetPassword consumes a password reset token and sets the new password,
+// bumping the token version so sessions issued before the reset stop working.
+// Following the emailed link also proves ownership of the address, so the
+// email is marked verified too.
+func (r *userTokenRepository) ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (uint, error) {
+	var userID uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		t, err := consumeToken(tx, tokenHash, models.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+		userID = t.UserID
+		// a fresh password also lifts any sign-in lockout
+		if err := tx.Model(&models.User{}).Where("user_id = ?", t.UserID).
+			Updates(map[string]interface{}{
+				"hashed_password":    hashedPassword,
+				"failed_login_count": 0,
+				"locked_until":       nil,
+				"token_version":      gorm.Expr("token_version + 1"),
+			}).Error; err != nil {
+			return err
+		}
+		return markEmailVerified(tx, t.UserID)
+	})
+	return userID, err
+}
+
+// VerifyEmail consumes an email verification token and marks the address verified
+func (r *userTokenRepository) VerifyEmail(ctx context.Context, tokenHash string) (uint, error) {
+	var userID uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		t, err := consumeToken(tx, tokenHash, models.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+		userID = t.UserID
+		return markEmailVerified(tx, t.UserID)
+	})
+	return userID, err
+}
+
+// consumeToken locks an unused, unexpired token and marks it used
+func consumeToken(tx *gorm.DB, tokenHash, purpose string) (*models.UserToken, error) {
+	var t models.UserToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, time.Now()).
+		First(&t).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTokenInvalid
+		}
+		return nil, err
+	}
+	if err := tx.Model(&t).Update("used_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func markEmailVerified(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.User{}).
+		Where("user_id = ? AND email_verified = ?", userID, false).
+		Updates(map[string]interface{}{"email_verified": true, "email_verified_at": time.Now()}).Error
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"property-backend/models"
+)
+
+// ValuationRepository defines property valuation history data access methods
+type ValuationRepository interface {
+	Create(ctx context.Context, v *models.PropertyValuation) error
+	ListByProperty(ctx context.Context, propertyID uint, page Page) ([]models.PropertyValuation, PageInfo, error)
+}
+
+type valuationRepository struct {
+	db *gorm.DB
+}
+
+// NewValuationRepository constructs a ValuationRepository
+func NewValuationRepository(db *gorm.DB) ValuationRepository {
+	return &valuationRepository{db: db}
+}
+
+// Create records a valuation for a property that has not been deleted and
+// updates the property's value from its latest valuation
+func (r *valuationRepository) Create(ctx context.Context, v *models.PropertyValuation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prop models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("property_id").First(&prop, v.PropertyID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+		return recordValuation(tx, v)
+	})
+}
+
+// ListByProperty returns a property's valuations, newest first. The order is
+// by date rather than primary key, so only offset pagination is supported.
+func (r *valuationRepository) ListByProperty(ctx context.Context, propertyID uint, page Page) ([]models.PropertyValuation, PageInfo, error) {
+	if page.AfterID > 0 {
+		return nil, PageInfo{}, ErrCursorUnsupported
+	}
+	var exists int64
+	if err := r.db.WithContext(ctx).Model(&models.Property{}).Where("property_id = ?", propertyID).Count(&exists).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	if exists == 0 {
+		return nil, PageInfo{}, ErrNotFound
+	}
+
+	q := r.db.WithContext(ctx).Model(&models.PropertyValuation{}).Where("property_id = ?", propertyID).Session(&gorm.Session{})
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	var valuations []models.PropertyValuation
+	if err := applyPage(q, page, "valuation_id").
+		Order("valued_on DESC, valuation_id DESC").
+		Find(&valuations).Error; err != nil {
+		return nil, PageInfo{}, err
+	}
+	valuations, info := finishPage(valuations, page, total, func(v *models.PropertyValuation) uint { return v.ValuationID })
+	info.NextCursor = ""
+	return valuations, info, nil
+}
+
+// manualValuation is the valuation recorded when a value is set on the property itself
+func manualValuation(propertyID uint, amount models.Money, actorID uint) *models.PropertyValuation {
+	return &models.PropertyValuation{
+		PropertyID: propertyID,
+		Amount:     amount,
+		ValuedOn:   time.Now(),
+		Source:     models.ValuationSourceManual,
+		CreatedBy:  actorID,
+	}
+}
+
+// recordValuation inserts v and copies the property's latest valuation into
+// property.value, so the two never disagree
+func recordValuation(tx *gorm.DB, v *models.PropertyValuation) error {
+	if err := tx.Create(v).Error; err != nil {
+		return fmt.Errorf("failed to create valuation: %w", err)
+	}
+	if err := tx.Exec(`
+		UPDATE property SET value = (
+			SELECT amount FROM property_valuations
+			WHERE property_id = ?
+			ORDER BY valued_on DESC, valuation_id DESC
+			LIMIT 1
+		) WHERE property_id = ?`, v.PropertyID, v.PropertyID).Error; err != nil {
+		return fmt.Errorf("failed to update property value: %w", err)
+	}
+	return nil
+}
@@ -3,10 +3,12 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
 )
 
 // AgreementRoutes registers agreement endpoints under /agreements
-func AgreementRoutes(rg *gin.RouterGroup, controller *controllers.AgreementController) {
+func AgreementRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.AgreementController) {
 	agmts := rg.Group("/agreements")
 	{
 		// Add rental agreement
@@ -15,13 +17,20 @@ func AgreementRoutes(rg *gin.RouterGroup, controller *controllers.AgreementContr
 		// @Accept json
 		// @Produce json
 		// @Router /api/v1/agreements [post]
-		agmts.POST("", controller.AddRentalAgreement)
+		agmts.POST("", auth.Require(services.PermAgreementsWrite), controller.AddRentalAgreement)
 
 		// Get all agreements
 		// @Summary Get all agreements
 		// @Tags Agreements
 		// @Produce json
 		// @Router /api/v1/agreements [get]
-		agmts.GET("", controller.GetAllAgreements)
+		agmts.GET("", auth.Require(services.PermAgreementsRead), controller.GetAllAgreements)
+
+		// Notice to vacate
+		// @Summary Record or withdraw notice to vacate
+		// @Tags Agreements
+		// @Accept json
+		// @Router /api/v1/agreements/{id}/notice [put]
+		agmts.PUT("/:id/notice", auth.Require(services.PermAgreementsWrite), controller.SetNoticeDate)
 	}
 }
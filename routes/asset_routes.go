@@ -3,10 +3,12 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
 )
 
 // AssetRoutes registers asset endpoints under /assets
-func AssetRoutes(rg *gin.RouterGroup, controller *controllers.AssetController) {
+func AssetRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.AssetController) {
 	assets := rg.Group("/assets")
 	{
 		// Add asset
@@ -15,13 +17,13 @@ func AssetRoutes(rg *gin.RouterGroup, controller *controllers.AssetController) {
 		// @Accept json
 		// @Produce json
 		// @Router /api/v1/assets [post]
-		assets.POST("", controller.AddAsset)
+		assets.POST("", auth.Require(services.PermAssetsWrite), controller.AddAsset)
 
 		// Get all assets
 		// @Summary Get all assets
 		// @Tags Assets
 		// @Produce json
 		// @Router /api/v1/assets [get]
-		assets.GET("", controller.GetAllAssets)
+		assets.GET("", auth.Require(services.PermAssetsRead), controller.GetAllAssets)
 	}
 }
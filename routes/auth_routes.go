@@ -24,5 +24,68 @@ func AuthRoutes(rg *gin.RouterGroup, controller *controllers.AuthController) {
 		// @Produce json
 		// @Router /api/v1/auth/signin [post]
 		auth.POST("/signin", controller.SignIn)
+
+		// Second sign-in step
+		// @Summary Complete sign in with a TOTP or recovery code
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/signin/2fa [post]
+		auth.POST("/signin/2fa", controller.SignInMFA)
+
+		// Enrollment demanded by a role policy
+		// @Summary Start the 2FA enrollment required by a role policy
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/signin/2fa/enroll [post]
+		auth.POST("/signin/2fa/enroll", controller.BeginMFAEnrollment)
+
+		// @Summary Confirm the required 2FA enrollment and sign in
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/signin/2fa/enroll/confirm [post]
+		auth.POST("/signin/2fa/enroll/confirm", controller.CompleteMFAEnrollment)
+
+		// Refresh
+		// @Summary Exchange a refresh token for a new token pair
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/refresh [post]
+		auth.POST("/refresh", controller.Refresh)
+
+		// Forgot password
+		// @Summary Email a password reset link
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/forgot-password [post]
+		auth.POST("/forgot-password", controller.ForgotPassword)
+
+		// Reset password
+		// @Summary Set a new password using a reset token
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/reset-password [post]
+		auth.POST("/reset-password", controller.ResetPassword)
+
+		// Verify email
+		// @Summary Confirm an email address using a verification token
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/verify-email [post]
+		auth.POST("/verify-email", controller.VerifyEmail)
+
+		// Resend verification
+		// @Summary Send a new email verification link
+		// @Tags Auth
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/auth/resend-verification [post]
+		auth.POST("/resend-verification", controller.ResendVerification)
 	}
 }
@@ -3,10 +3,12 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
 )
 
 // ContractRoutes registers contract endpoints under /contracts
-func ContractRoutes(rg *gin.RouterGroup, controller *controllers.ContractController) {
+func ContractRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.ContractController) {
 	contracts := rg.Group("/contracts")
 	{
 		// Add contract
@@ -15,27 +17,27 @@ func ContractRoutes(rg *gin.RouterGroup, controller *controllers.ContractControl
 		// @Accept json
 		// @Produce json
 		// @Router /api/v1/contracts [post]
-		contracts.POST("", controller.AddContract)
+		contracts.POST("", auth.Require(services.PermContractsWrite), controller.AddContract)
 
 		// Get all contracts
 		// @Summary Get all contracts
 		// @Tags Contracts
 		// @Produce json
 		// @Router /api/v1/contracts [get]
-		contracts.GET("", controller.GetAllContracts)
+		contracts.GET("", auth.Require(services.PermContractsRead), controller.GetAllContracts)
 
 		// Lease contracts
 		// @Summary Get all lease contracts
 		// @Tags Contracts
 		// @Produce json
 		// @Router /api/v1/contracts/lease [get]
-		contracts.GET("/lease", controller.GetLeaseContracts)
+		contracts.GET("/lease", auth.Require(services.PermContractsRead), controller.GetLeaseContracts)
 
 		// AMC contracts
 		// @Summary Get all AMC contracts
 		// @Tags Contracts
 		// @Produce json
 		// @Router /api/v1/contracts/amc [get]
-		contracts.GET("/amc", controller.GetAMCContracts)
+		contracts.GET("/amc", auth.Require(services.PermContractsRead), controller.GetAMCContracts)
 	}
 }
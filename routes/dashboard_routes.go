@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
+)
+
+// DashboardRoutes registers the portfolio dashboard under /dashboard
+func DashboardRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.DashboardController) {
+	dashboard := rg.Group("/dashboard")
+	{
+		// Portfolio summary; it reports on properties, agreements and contracts,
+		// so it needs read access to all three
+		// @Summary Portfolio dashboard summary
+		// @Tags Dashboard
+		// @Produce json
+		// @Router /api/v1/dashboard/summary [get]
+		dashboard.GET("/summary",
+			auth.Require(services.PermPropertiesRead),
+			auth.Require(services.PermAgreementsRead),
+			auth.Require(services.PermContractsRead),
+			controller.Summary)
+	}
+}
@@ -0,0 +1,61 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
+)
+
+// MFARoutes registers two-factor enrollment and administration endpoints
+func MFARoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.MFAController) {
+	me := rg.Group("/users/me/2fa", auth.RequireUserSession())
+	{
+		// Current user's 2FA (any signed-in user; API keys are refused)
+		// @Summary Show the current user's two-factor status
+		// @Tags Two-Factor
+		// @Produce json
+		// @Router /api/v1/users/me/2fa [get]
+		me.GET("", controller.GetMyMFAStatus)
+
+		// @Summary Generate a TOTP secret and provisioning URI
+		// @Tags Two-Factor
+		// @Produce json
+		// @Router /api/v1/users/me/2fa/enroll [post]
+		me.POST("/enroll", controller.BeginEnrollment)
+
+		// @Summary Enable 2FA by confirming a code
+		// @Tags Two-Factor
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/users/me/2fa/confirm [post]
+		me.POST("/confirm", controller.ConfirmEnrollment)
+
+		// @Summary Replace the current user's recovery codes
+		// @Tags Two-Factor
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/users/me/2fa/recovery-codes [post]
+		me.POST("/recovery-codes", controller.RegenerateRecoveryCodes)
+
+		// @Summary Turn off 2FA for the current user
+		// @Tags Two-Factor
+		// @Accept json
+		// @Router /api/v1/users/me/2fa/disable [post]
+		me.POST("/disable", controller.DisableMFA)
+	}
+
+	// Reset a user's 2FA
+	// @Summary Remove 2FA from a user (admin only)
+	// @Tags Two-Factor
+	// @Router /api/v1/users/{id}/2fa [delete]
+	rg.DELETE("/users/:id/2fa", auth.Require(services.PermUsersManage), controller.ResetUserMFA)
+
+	// Role 2FA policy
+	// @Summary Require 2FA for a role (admin only)
+	// @Tags Two-Factor
+	// @Accept json
+	// @Produce json
+	// @Router /api/v1/roles/{id}/2fa-policy [put]
+	rg.PUT("/roles/:id/2fa-policy", auth.Require(services.PermUsersManage), controller.SetRolePolicy)
+}
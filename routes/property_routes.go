@@ -3,10 +3,12 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
 )
 
 // PropertyRoutes registers property-related routes under /properties
-func PropertyRoutes(rg *gin.RouterGroup, controller *controllers.PropertyController) {
+func PropertyRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.PropertyController) {
 	props := rg.Group("/properties")
 	{
 		// Total properties
@@ -14,14 +16,21 @@ func PropertyRoutes(rg *gin.RouterGroup, controller *controllers.PropertyControl
 		// @Tags Properties
 		// @Produce json
 		// @Router /api/v1/properties/total [get]
-		props.GET("/total", controller.TotalProperties)
+		props.GET("/total", auth.Require(services.PermPropertiesRead), controller.TotalProperties)
 
 		// Active rental properties count
 		// @Summary Get active rental properties count
 		// @Tags Properties
 		// @Produce json
 		// @Router /api/v1/properties/active-rental/count [get]
-		props.GET("/active-rental/count", controller.ActiveRentalPropertyCount)
+		props.GET("/active-rental/count", auth.Require(services.PermPropertiesRead), controller.ActiveRentalPropertyCount)
+
+		// Occupancy breakdown
+		// @Summary Count properties by occupancy status
+		// @Tags Properties
+		// @Produce json
+		// @Router /api/v1/properties/occupancy [get]
+		props.GET("/occupancy", auth.Require(services.PermPropertiesRead), controller.OccupancyCounts)
 
 		// Add property
 		// @Summary Add a new property
@@ -29,27 +38,70 @@ func PropertyRoutes(rg *gin.RouterGroup, controller *controllers.PropertyControl
 		// @Accept json
 		// @Produce json
 		// @Router /api/v1/properties [post]
-		props.POST("", controller.AddProperty)
+		props.POST("", auth.Require(services.PermPropertiesWrite), controller.AddProperty)
+
+		// Search properties
+		// @Summary Search properties with filters, sorting and pagination
+		// @Tags Properties
+		// @Produce json
+		// @Router /api/v1/properties [get]
+		props.GET("", auth.Require(services.PermPropertiesRead), controller.ListProperties)
+
+		// Get property
+		// @Summary Get a property with all its details
+		// @Tags Properties
+		// @Produce json
+		// @Router /api/v1/properties/{id} [get]
+		props.GET("/:id", auth.Require(services.PermPropertiesRead), controller.GetProperty)
+
+		// Replace property
+		// @Summary Replace a property and all its detail sections
+		// @Tags Properties
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/properties/{id} [put]
+		props.PUT("/:id", auth.Require(services.PermPropertiesWrite), controller.UpdateProperty)
 
-		// Agricultural properties
-		// @Summary List agricultural land properties
+		// Partially update property
+		// @Summary Partially update a property and its detail sections
 		// @Tags Properties
+		// @Accept json
 		// @Produce json
-		// @Router /api/v1/properties/agricultural [get]
-		props.GET("/agricultural", controller.AgriculturalLandProperties)
+		// @Router /api/v1/properties/{id} [patch]
+		props.PATCH("/:id", auth.Require(services.PermPropertiesWrite), controller.PatchProperty)
 
-		// Residential properties
-		// @Summary List residential land properties
+		// Delete property
+		// @Summary Soft-delete a property
+		// @Tags Properties
+		// @Router /api/v1/properties/{id} [delete]
+		props.DELETE("/:id", auth.Require(services.PermPropertiesWrite), controller.DeleteProperty)
+
+		// Restore property
+		// @Summary Restore a soft-deleted property
 		// @Tags Properties
 		// @Produce json
-		// @Router /api/v1/properties/residential [get]
-		props.GET("/residential", controller.ResidentialLandProperties)
+		// @Router /api/v1/properties/{id}/restore [post]
+		props.POST("/:id/restore", auth.Require(services.PermPropertiesWrite), controller.RestoreProperty)
 
-		// Commercial properties
-		// @Summary List commercial land properties
+		// Record a valuation
+		// @Summary Record a valuation of a property
 		// @Tags Properties
+		// @Accept json
 		// @Produce json
-		// @Router /api/v1/properties/commercial [get]
-		props.GET("/commercial", controller.CommercialLandProperties)
+		// @Router /api/v1/properties/{id}/valuations [post]
+		props.POST("/:id/valuations", auth.Require(services.PermPropertiesWrite), controller.AddValuation)
+
+		// Valuation history
+		// @Summary List a property's valuation history
+		// @Tags Properties
+		// @Produce json
+		// @Router /api/v1/properties/{id}/valuations [get]
+		props.GET("/:id/valuations", auth.Require(services.PermPropertiesRead), controller.ListValuations)
+
+		// Purge property
+		// @Summary Permanently remove a soft-deleted property (admin only)
+		// @Tags Properties
+		// @Router /api/v1/properties/{id}/purge [delete]
+		props.DELETE("/:id/purge", auth.Require(services.PermPropertiesPurge), controller.PurgeProperty)
 	}
 }
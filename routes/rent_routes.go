@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
+)
+
+// RentRoutes registers rent schedule and payment routes under /agreements/:id
+// and the portfolio-wide arrears listing under /rent
+func RentRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.RentController) {
+	agmts := rg.Group("/agreements")
+	{
+		// Rent schedule
+		// @Summary Rent schedule of an agreement
+		// @Tags Rent
+		// @Produce json
+		// @Router /api/v1/agreements/{id}/schedule [get]
+		agmts.GET("/:id/schedule", auth.Require(services.PermAgreementsRead), controller.Schedule)
+
+		// Rent ledger
+		// @Summary Rent ledger of an agreement
+		// @Tags Rent
+		// @Produce json
+		// @Router /api/v1/agreements/{id}/ledger [get]
+		agmts.GET("/:id/ledger", auth.Require(services.PermAgreementsRead), controller.Ledger)
+
+		// Record a payment
+		// @Summary Record rent received against an agreement
+		// @Tags Rent
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/agreements/{id}/payments [post]
+		agmts.POST("/:id/payments", auth.Require(services.PermRentWrite), controller.AddPayment)
+
+		// Effective rent
+		// @Summary Monthly rent of an agreement on a date
+		// @Tags Rent
+		// @Produce json
+		// @Router /api/v1/agreements/{id}/effective-rent [get]
+		agmts.GET("/:id/effective-rent", auth.Require(services.PermAgreementsRead), controller.EffectiveRent)
+	}
+
+	rent := rg.Group("/rent")
+	{
+		// Overdue rent
+		// @Summary Agreements with overdue rent across the portfolio
+		// @Tags Rent
+		// @Produce json
+		// @Router /api/v1/rent/overdue [get]
+		rent.GET("/overdue", auth.Require(services.PermAgreementsRead), controller.Overdue)
+	}
+}
@@ -3,20 +3,38 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"property-backend/controllers"
+	"property-backend/middleware"
 )
 
 // RegisterRoutes registers all API routes under a provided RouterGroup
 func RegisterRoutes(api *gin.RouterGroup,
+	authMiddleware *middleware.AuthMiddleware,
 	authController *controllers.AuthController,
 	propertyController *controllers.PropertyController,
 	agreementController *controllers.AgreementController,
 	assetController *controllers.AssetController,
 	contractController *controllers.ContractController,
+	userController *controllers.UserController,
+	serviceAccountController *controllers.ServiceAccountController,
+	mfaController *controllers.MFAController,
+	dashboardController *controllers.DashboardController,
+	rentController *controllers.RentController,
 ) {
-	// domain-specific routes
+	// public routes
 	AuthRoutes(api, authController)
-	PropertyRoutes(api, propertyController)
-	AgreementRoutes(api, agreementController)
-	AssetRoutes(api, assetController)
-	ContractRoutes(api, contractController)
+
+	// everything else requires a valid access token; each route then checks its permission
+	protected := api.Group("")
+	protected.Use(authMiddleware.Authenticate())
+
+	// domain-specific routes
+	PropertyRoutes(protected, authMiddleware, propertyController)
+	AgreementRoutes(protected, authMiddleware, agreementController)
+	AssetRoutes(protected, authMiddleware, assetController)
+	ContractRoutes(protected, authMiddleware, contractController)
+	UserRoutes(protected, authMiddleware, userController)
+	ServiceAccountRoutes(protected, authMiddleware, serviceAccountController)
+	MFARoutes(protected, authMiddleware, mfaController)
+	DashboardRoutes(protected, authMiddleware, dashboardController)
+	RentRoutes(protected, authMiddleware, rentController)
 }
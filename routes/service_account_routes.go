@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
+)
+
+// ServiceAccountRoutes registers service account and API key endpoints
+func ServiceAccountRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.ServiceAccountController) {
+	accounts := rg.Group("/service-accounts")
+	{
+		// Create service account
+		// @Summary Create a service account and its first API key (admin only)
+		// @Tags Service Accounts
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/service-accounts [post]
+		accounts.POST("", auth.Require(services.PermUsersManage), controller.CreateServiceAccount)
+
+		// List service accounts
+		// @Summary List service accounts (admin only)
+		// @Tags Service Accounts
+		// @Produce json
+		// @Router /api/v1/service-accounts [get]
+		accounts.GET("", auth.Require(services.PermUsersManage), controller.ListServiceAccounts)
+
+		// Issue key
+		// @Summary Issue an additional API key (admin only)
+		// @Tags Service Accounts
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/service-accounts/{id}/keys [post]
+		accounts.POST("/:id/keys", auth.Require(services.PermUsersManage), controller.CreateKey)
+
+		// List keys
+		// @Summary List a service account's API keys (admin only)
+		// @Tags Service Accounts
+		// @Produce json
+		// @Router /api/v1/service-accounts/{id}/keys [get]
+		accounts.GET("/:id/keys", auth.Require(services.PermUsersManage), controller.ListKeys)
+	}
+
+	keys := rg.Group("/api-keys")
+	{
+		// Rotate key
+		// @Summary Revoke an API key and issue a replacement (admin only)
+		// @Tags Service Accounts
+		// @Produce json
+		// @Router /api/v1/api-keys/{id}/rotate [post]
+		keys.POST("/:id/rotate", auth.Require(services.PermUsersManage), controller.RotateKey)
+
+		// Revoke key
+		// @Summary Revoke an API key (admin only)
+		// @Tags Service Accounts
+		// @Router /api/v1/api-keys/{id} [delete]
+		keys.DELETE("/:id", auth.Require(services.PermUsersManage), controller.RevokeKey)
+	}
+}
@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"property-backend/controllers"
+	"property-backend/middleware"
+	"property-backend/services"
+)
+
+// UserRoutes registers user administration endpoints under /users and /roles
+func UserRoutes(rg *gin.RouterGroup, auth *middleware.AuthMiddleware, controller *controllers.UserController) {
+	// List roles
+	// @Summary List all roles
+	// @Tags Users
+	// @Produce json
+	// @Router /api/v1/roles [get]
+	rg.GET("/roles", auth.Require(services.PermUsersManage), controller.ListRoles)
+
+	users := rg.Group("/users")
+	{
+		// Current user's profile (any authenticated user)
+		// @Summary Get the current user's profile
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users/me [get]
+		users.GET("/me", controller.GetMe)
+
+		// @Summary Update the current user's name and phone number (not with an API key)
+		// @Tags Users
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/users/me [patch]
+		users.PATCH("/me", auth.RequireUserSession(), controller.UpdateMe)
+
+		// @Summary Change the current user's password (not with an API key)
+		// @Tags Users
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/users/me/password [post]
+		users.POST("/me/password", auth.RequireUserSession(), controller.ChangeMyPassword)
+
+		// List users
+		// @Summary List users (admin only)
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users [get]
+		users.GET("", auth.Require(services.PermUsersManage), controller.ListUsers)
+
+		// Get user
+		// @Summary Get a user (admin only)
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users/{id} [get]
+		users.GET("/:id", auth.Require(services.PermUsersManage), controller.GetUser)
+
+		// Update user
+		// @Summary Update a user's profile (admin only)
+		// @Tags Users
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/users/{id} [put]
+		users.PUT("/:id", auth.Require(services.PermUsersManage), controller.UpdateUser)
+
+		// Deactivate user
+		// @Summary Deactivate a user (admin only)
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users/{id} [delete]
+		users.DELETE("/:id", auth.Require(services.PermUsersManage), controller.DeactivateUser)
+
+		// Reactivate user
+		// @Summary Reactivate a deactivated user (admin only)
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users/{id}/activate [post]
+		users.POST("/:id/activate", auth.Require(services.PermUsersManage), controller.ActivateUser)
+
+		// Invite user
+		// @Summary Create a user account with the given roles (admin only)
+		// @Tags Users
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/users/invite [post]
+		users.POST("/invite", auth.Require(services.PermUsersManage), controller.InviteUser)
+
+		// User roles
+		// @Summary List the roles held by a user
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users/{id}/roles [get]
+		users.GET("/:id/roles", auth.Require(services.PermUsersManage), controller.GetUserRoles)
+
+		// Grant role
+		// @Summary Grant a role to a user
+		// @Tags Users
+		// @Accept json
+		// @Produce json
+		// @Router /api/v1/users/{id}/roles [post]
+		users.POST("/:id/roles", auth.Require(services.PermUsersManage), controller.GrantRole)
+
+		// Revoke role
+		// @Summary Revoke a role from a user
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users/{id}/roles/{role_id} [delete]
+		users.DELETE("/:id/roles/:role_id", auth.Require(services.PermUsersManage), controller.RevokeRole)
+
+		// Unlock user
+		// @Summary Clear a sign-in lockout (admin only)
+		// @Tags Users
+		// @Produce json
+		// @Router /api/v1/users/{id}/unlock [post]
+		users.POST("/:id/unlock", auth.Require(services.PermUsersManage), controller.UnlockUser)
+	}
+}
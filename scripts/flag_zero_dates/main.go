@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"property-backend/config"
+	"property-backend/models"
+)
+
+// zeroDateReason is the data_cleanup_report reason for the flagged rows
+const zeroDateReason = "zero date: saved before start/end dates were persisted"
+
+var dateColumns = []struct {
+	table    string
+	idColumn string
+	column   string
+}{
+	{"agreement", "agreement_id", "start_date"},
+	{"agreement", "agreement_id", "end_date"},
+	{"contracts", "contract_id", "start_date"},
+	{"contracts", "contract_id", "end_date"},
+}
+
+// Agreements and contracts created before their dates were saved hold the
+// zero timestamp (0001-01-01). They can never count as active or expiring, and
+// the real dates have to come from the paper agreements, so this only lists
+// them in data_cleanup_report for someone to fix by hand.
+//
+//	go run ./scripts/flag_zero_dates [-dry-run]
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print the rows without recording them")
+	flag.Parse()
+
+	// ConnectDatabase also migrates, so data_cleanup_report exists
+	config.ConnectDatabase()
+	db := config.DB
+
+	total := 0
+	for _, dc := range dateColumns {
+		// anything before 1900 is a zero date shifted by the session time zone;
+		// rows already in the report are skipped so the tool can be rerun
+		var ids []uint
+		err := db.Table(dc.table).
+			Where(fmt.Sprintf("%s IS NULL OR %s < '1900-01-01'", dc.column, dc.column)).
+			Where(fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM data_cleanup_report r
+				WHERE r.source_table = ? AND r.source_column = ? AND r.row_id = %s.%s)`, dc.table, dc.idColumn),
+				dc.table, dc.column).
+			Order(dc.idColumn).
+			Pluck(dc.idColumn, &ids).Error
+		if err != nil {
+			log.Fatalf("failed to scan %s.%s: %v", dc.table, dc.column, err)
+		}
+		for _, id := range ids {
+			log.Printf("%s %d: %s is not set", dc.table, id, dc.column)
+		}
+		total += len(ids)
+		if *dryRun || len(ids) == 0 {
+			continue
+		}
+
+		reports := make([]models.DataCleanupReport, 0, len(ids))
+		for _, id := range ids {
+			reports = append(reports, models.DataCleanupReport{
+				SourceTable:  dc.table,
+				SourceColumn: dc.column,
+				RowID:        id,
+				Reason:       zeroDateReason,
+			})
+		}
+		if err := db.CreateInBatches(reports, 500).Error; err != nil {
+			log.Fatalf("failed to record %s.%s: %v", dc.table, dc.column, err)
+		}
+	}
+
+	if *dryRun {
+		log.Printf("✅ %d zero dates found (dry run, nothing recorded)", total)
+		return
+	}
+	log.Printf("✅ %d zero dates recorded in data_cleanup_report", total)
+}
@@ -143,8 +143,8 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				PropertyName:   "Green Valley Estate",
 				PropertyTypeID: residential.PropertyTypeID,
 				UserID:         user.UserID,
-				Value:          "5000000",
-				Income:         "50000",
+				Value:          money(5000000),
+				Income:         money(50000),
 				OriginalDeed:   "yes",
 			},
 			address: models.Address{
@@ -162,9 +162,9 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				SyNo:       "78/A",
 				KhataNo:    "2023-45",
 				MrNo:       "789",
-				Acre:       "2.5",
-				Gunte:      "100",
-				Karab:      "50",
+				Acre:       number(2.5),
+				Gunte:      number(100),
+				Karab:      number(50),
 				Converted:  "yes",
 				Purpose:    "residential",
 				KhuskiTari: "dry",
@@ -185,8 +185,8 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				PhoneNumber:         "9876543210",
 			},
 			buildingDetails: models.PropertyBuildingDetails{
-				PlotSize:           "2500 sq.ft",
-				BuiltUpArea:        "1800 sq.ft",
+				PlotSize:           number(2500),
+				BuiltUpArea:        number(1800),
 				YearOfConstruction: "2015",
 				ApplicationNo:      "APP2015001",
 			},
@@ -201,8 +201,8 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				PropertyName:   "Sunshine Commercial Complex",
 				PropertyTypeID: commercial.PropertyTypeID,
 				UserID:         user.UserID,
-				Value:          "8000000",
-				Income:         "100000",
+				Value:          money(8000000),
+				Income:         money(100000),
 				OriginalDeed:   "yes",
 			},
 			address: models.Address{
@@ -220,9 +220,9 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				SyNo:       "92/B",
 				KhataNo:    "2022-88",
 				MrNo:       "456",
-				Acre:       "1.0",
-				Gunte:      "40",
-				Karab:      "40",
+				Acre:       number(1.0),
+				Gunte:      number(40),
+				Karab:      number(40),
 				Converted:  "yes",
 				Purpose:    "commercial",
 				KhuskiTari: "irrigated",
@@ -243,8 +243,8 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				PhoneNumber:         "9876543211",
 			},
 			buildingDetails: models.PropertyBuildingDetails{
-				PlotSize:           "4000 sq.ft",
-				BuiltUpArea:        "3500 sq.ft",
+				PlotSize:           number(4000),
+				BuiltUpArea:        number(3500),
 				YearOfConstruction: "2018",
 				ApplicationNo:      "APP2018025",
 			},
@@ -259,8 +259,8 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				PropertyName:   "Farmland Paradise",
 				PropertyTypeID: agricultural.PropertyTypeID,
 				UserID:         user.UserID,
-				Value:          "3000000",
-				Income:         "25000",
+				Value:          money(3000000),
+				Income:         money(25000),
 				OriginalDeed:   "yes",
 			},
 			address: models.Address{
@@ -278,9 +278,9 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				SyNo:       "34/C",
 				KhataNo:    "2020-12",
 				MrNo:       "234",
-				Acre:       "5.0",
-				Gunte:      "200",
-				Karab:      "180",
+				Acre:       number(5.0),
+				Gunte:      number(200),
+				Karab:      number(180),
 				Converted:  "no",
 				Purpose:    "agriculture",
 				KhuskiTari: "irrigated",
@@ -301,8 +301,8 @@ func SeedSamplePropertyData(db *gorm.DB) {
 				PhoneNumber:         "9876543212",
 			},
 			buildingDetails: models.PropertyBuildingDetails{
-				PlotSize:           "217800 sq.ft",
-				BuiltUpArea:        "500 sq.ft",
+				PlotSize:           number(217800),
+				BuiltUpArea:        number(500),
 				YearOfConstruction: "2010",
 				ApplicationNo:      "APP2010010",
 			},
@@ -384,3 +384,13 @@ func SeedSamplePropertyData(db *gorm.DB) {
 
 	log.Println("✅ Sample property data seeding completed")
 }
+
+// money converts whole rupees to a Money pointer for the sample rows
+func money(rupees int64) *models.Money {
+	m := models.Money(rupees * 100)
+	return &m
+}
+
+func number(f float64) *float64 {
+	return &f
+}
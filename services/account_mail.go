@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"property-backend/mailer"
+	"property-backend/models"
+	"property-backend/repositories"
+	"property-backend/utils"
+)
+
+const (
+	passwordResetTokenTTL     = time.Hour
+	emailVerificationTokenTTL = 48 * time.Hour
+	invitationTokenTTL        = 72 * time.Hour
+)
+
+// issueUserToken creates a single-use token and returns the plaintext value; only its hash is stored
+func issueUserToken(ctx context.Context, tokens repositories.UserTokenRepository, userID uint, purpose string, ttl time.Duration) (string, error) {
+	plain, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	t := models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(plain),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := tokens.Create(ctx, &t); err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+func sendPasswordResetMail(ctx context.Context, sender mailer.Sender, u *models.User, token string) error {
+	return sender.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %s and can only be used once.\n\n%s\n\nIf you did not ask for this you can ignore this email.",
+			u.FirstName, passwordResetTokenTTL, accountLink("reset-password", token)),
+	})
+}
+
+func sendVerificationMail(ctx context.Context, sender mailer.Sender, u *models.User, token string) error {
+	return sender.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address using the link below. It expires in %s.\n\n%s",
+			u.FirstName, emailVerificationTokenTTL, accountLink("verify-email", token)),
+	})
+}
+
+func sendInvitationMail(ctx context.Context, sender mailer.Sender, u *models.User, token string) error {
+	return sender.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "You have been invited to the property portal",
+		Body: fmt.Sprintf("Hi %s,\n\nAn account has been created for you. Set your password using the link below; it expires in %s.\n\n%s",
+			u.FirstName, invitationTokenTTL, accountLink("reset-password", token)),
+	})
+}
+
+// accountLink builds the link sent in account emails from APP_BASE_URL
+func accountLink(path, token string) string {
+	base := os.Getenv("APP_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	return fmt.Sprintf("%s/%s?token=%s", strings.TrimRight(base, "/"), path, url.QueryEscape(token))
+}
@@ -2,6 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"property-backend/models"
 	"property-backend/repositories"
@@ -10,7 +15,8 @@ import (
 // AgreementService defines agreement domain logic
 type AgreementService interface {
 	AddRentalAgreement(ctx context.Context, a *models.Agreement) (int64, error)
-	GetAllAgreements(ctx context.Context) ([]models.Agreement, error)
+	GetAllAgreements(ctx context.Context, page repositories.Page) ([]models.Agreement, repositories.PageInfo, error)
+	SetNoticeDate(ctx context.Context, id uint, date *time.Time) error
 }
 
 type agreementService struct {
@@ -23,9 +29,72 @@ func NewAgreementService(repo repositories.AgreementRepository) AgreementService
 }
 
 func (s *agreementService) AddRentalAgreement(ctx context.Context, a *models.Agreement) (int64, error) {
-	return s.repo.CreateRental(ctx, a)
+	if a.StartDate.IsZero() || a.EndDate.IsZero() {
+		return 0, fmt.Errorf("%w: start_date and end_date are required", ErrInvalidAgreementInput)
+	}
+	if !a.EndDate.After(a.StartDate) {
+		return 0, fmt.Errorf("%w: end_date must be after start_date", ErrInvalidAgreementInput)
+	}
+	if a.Rent <= 0 {
+		return 0, fmt.Errorf("%w: rent must be positive", ErrInvalidAgreementInput)
+	}
+	if a.RentFrequency == "" {
+		a.RentFrequency = models.RentMonthly
+	}
+	if _, ok := models.RentFrequencyMonths[a.RentFrequency]; !ok {
+		return 0, fmt.Errorf("%w: rent_frequency must be %s or %s", ErrInvalidAgreementInput, models.RentMonthly, models.RentQuarterly)
+	}
+	if err := checkEscalation(a); err != nil {
+		return 0, err
+	}
+	// an *repositories.OverlapError is passed through so the clashing agreement can be named
+	id, err := s.repo.CreateRental(ctx, a)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return 0, ErrPropertyNotFound
+	}
+	return id, err
 }
 
-func (s *agreementService) GetAllAgreements(ctx context.Context) ([]models.Agreement, error) {
-	return s.repo.ListAll(ctx)
+func (s *agreementService) GetAllAgreements(ctx context.Context, page repositories.Page) ([]models.Agreement, repositories.PageInfo, error) {
+	return s.repo.ListAll(ctx, page)
+}
+
+// SetNoticeDate records (or with nil withdraws) notice to vacate, which puts
+// the property in its notice period once every running agreement has notice
+func (s *agreementService) SetNoticeDate(ctx context.Context, id uint, date *time.Time) error {
+	if err := s.repo.SetNoticeDate(ctx, id, date); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrAgreementNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkEscalation validates an agreement's escalation clause. With no
+// escalation_type the other fields must be unset, so a half-filled clause is
+// not silently ignored.
+func checkEscalation(a *models.Agreement) error {
+	if a.EscalationType == "" {
+		if a.EscalationValue != 0 || a.EscalationIntervalMonths != 0 || a.EscalationCap != nil {
+			return fmt.Errorf("%w: escalation_type is required with an escalation", ErrInvalidAgreementInput)
+		}
+		return nil
+	}
+	if !slices.Contains(models.EscalationTypes, a.EscalationType) {
+		return fmt.Errorf("%w: escalation_type must be one of %s", ErrInvalidAgreementInput, strings.Join(models.EscalationTypes, ", "))
+	}
+	if a.EscalationValue <= 0 {
+		return fmt.Errorf("%w: escalation_value must be positive", ErrInvalidAgreementInput)
+	}
+	if a.EscalationType == models.EscalationPercentage && a.EscalationValue > 100 {
+		return fmt.Errorf("%w: escalation_value must be at most 100 percent", ErrInvalidAgreementInput)
+	}
+	if a.EscalationIntervalMonths <= 0 {
+		return fmt.Errorf("%w: escalation_interval_months must be positive", ErrInvalidAgreementInput)
+	}
+	if a.EscalationCap != nil && *a.EscalationCap < a.Rent {
+		return fmt.Errorf("%w: escalation_cap must not be below the rent", ErrInvalidAgreementInput)
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	"property-backend/models"
 	"property-backend/repositories"
@@ -10,7 +11,7 @@ import (
 // AssetService defines asset domain logic
 type AssetService interface {
 	AddAsset(ctx context.Context, a *models.Asset) (int64, error)
-	GetAllAssets(ctx context.Context) ([]models.Asset, error)
+	GetAllAssets(ctx context.Context, page repositories.Page) ([]models.Asset, repositories.PageInfo, error)
 }
 
 type assetService struct {
@@ -23,9 +24,12 @@ func NewAssetService(repo repositories.AssetRepository) AssetService {
 }
 
 func (s *assetService) AddAsset(ctx context.Context, a *models.Asset) (int64, error) {
+	if a.AssetCost != nil && *a.AssetCost < 0 {
+		return 0, fmt.Errorf("%w: cost must not be negative", ErrInvalidAssetInput)
+	}
 	return s.repo.Create(ctx, a)
 }
 
-func (s *assetService) GetAllAssets(ctx context.Context) ([]models.Asset, error) {
-	return s.repo.ListAll(ctx)
+func (s *assetService) GetAllAssets(ctx context.Context, page repositories.Page) ([]models.Asset, repositories.PageInfo, error) {
+	return s.repo.ListAll(ctx, page)
 }
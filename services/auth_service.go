@@ -2,39 +2,442 @@ package services
 
 import (
 	"context"
+	"errors"
+	"log"
+	"time"
 
+	"gorm.io/gorm"
+	"property-backend/dto"
+	"property-backend/mailer"
 	"property-backend/models"
 	"property-backend/repositories"
 	"property-backend/utils"
 )
 
+// AuthTokens is the token pair issued on a successful sign in or refresh,
+// together with the signed-in user's public profile
+type AuthTokens struct {
+	AccessToken  string           `json:"access_token"`
+	RefreshToken string           `json:"refresh_token"`
+	TokenType    string           `json:"token_type"`
+	ExpiresIn    int64            `json:"expires_in"`
+	User         dto.UserResponse `json:"user"`
+}
+
+// MFAChallenge is returned instead of tokens when the password step of sign
+// in succeeded but a second factor is still needed. EnrollmentRequired means
+// a role policy requires 2FA the user has not set up yet; the token then has
+// to be used with the enrollment endpoints instead of the code endpoint.
+type MFAChallenge struct {
+	MFARequired        bool   `json:"mfa_required"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+	ChallengeToken     string `json:"challenge_token"`
+	ExpiresIn          int64  `json:"expires_in"`
+}
+
+// SignInResult holds either the issued tokens or a pending MFA challenge
+type SignInResult struct {
+	Tokens    *AuthTokens
+	Challenge *MFAChallenge
+}
+
 // AuthService defines authentication related business logic
 type AuthService interface {
 	SignUp(ctx context.Context, u *models.User) (int64, error)
-	SignIn(ctx context.Context, email, password string) (*models.User, error)
+	SignIn(ctx context.Context, email, password, clientIP string) (*SignInResult, error)
+	CompleteMFASignIn(ctx context.Context, challengeToken, code, clientIP string) (*AuthTokens, error)
+	BeginMFAEnrollment(ctx context.Context, enrollmentToken, clientIP string) (*MFAEnrollment, error)
+	CompleteMFAEnrollment(ctx context.Context, enrollmentToken, code, clientIP string) (*AuthTokens, []string, error)
+	Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error)
+	Authenticate(ctx context.Context, accessToken string) (*models.User, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (*models.User, []Permission, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
 }
 
 type authService struct {
-	repo repositories.AuthRepository
+	repo     repositories.AuthRepository
+	roles    repositories.RoleRepository
+	tokens   repositories.UserTokenRepository
+	security repositories.SecurityRepository
+	apiKeys  repositories.APIKeyRepository
+	mfa      MFAService
+	mail     mailer.Sender
 }
 
 // NewAuthService constructs an AuthService
-func NewAuthService(repo repositories.AuthRepository) AuthService {
-	return &authService{repo: repo}
+func NewAuthService(repo repositories.AuthRepository, roles repositories.RoleRepository, tokens repositories.UserTokenRepository, security repositories.SecurityRepository, apiKeys repositories.APIKeyRepository, mfa MFAService, mail mailer.Sender) AuthService {
+	return &authService{repo: repo, roles: roles, tokens: tokens, security: security, apiKeys: apiKeys, mfa: mfa, mail: mail}
 }
 
+// SignUp registers a self-service user. The caller never chooses the role:
+// new accounts always start with the least-privileged viewer role.
 func (s *authService) SignUp(ctx context.Context, u *models.User) (int64, error) {
-	return s.repo.Create(ctx, u)
+	if err := ensureEmailAvailable(ctx, s.repo, u.Email); err != nil {
+		return 0, err
+	}
+	role, err := s.roles.GetByName(ctx, models.RoleViewer)
+	if err != nil {
+		return 0, err
+	}
+	u.RoleID = role.RolesMasterID
+	id, err := s.repo.Create(ctx, u)
+	if err != nil {
+		return 0, err
+	}
+	// the account exists either way; a failed email can be retried via resend-verification
+	if err := s.sendVerification(ctx, u); err != nil {
+		log.Println("⚠️ could not send verification email:", err)
+	}
+	return id, nil
 }
 
-func (s *authService) SignIn(ctx context.Context, email, password string) (*models.User, error) {
+// SignIn checks credentials with brute-force protection: requests from an IP
+// with too many recent failures are refused outright, and repeated failures
+// against one account lock it for a growing period of time.
+//
+// Users with 2FA (or whose role requires it) get an MFA challenge instead of
+// tokens. Failed-login counters are only reset once the second step passes,
+// so wrong codes count towards the account lockout as well.
+func (s *authService) SignIn(ctx context.Context, email, password, clientIP string) (*SignInResult, error) {
+	if err := s.checkIPThrottle(ctx, email, clientIP); err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := s.registerFailure(ctx, nil, email, clientIP); err != nil {
+				return nil, err
+			}
+			return nil, ErrInvalidCredentials
+		}
 		return nil, err
 	}
-	// verify password using bcrypt
-	if user == nil || !utils.CheckPasswordHash(password, user.HashedPassword) {
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		if err := s.recordAttempt(ctx, email, clientIP, false); err != nil {
+			return nil, err
+		}
+		return nil, ErrAccountLocked
+	}
+	// verify password using bcrypt; service accounts only authenticate with API keys
+	if user.IsServiceAccount || !utils.CheckPasswordHash(password, user.HashedPassword) {
+		if err := s.registerFailure(ctx, user, email, clientIP); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
+
+	if !user.IsActive {
+		return nil, ErrAccountDisabled
+	}
+
+	if user.TOTPEnabled {
+		challenge, err := newMFAChallenge(user, utils.MFAChallengeTokenType, mfaChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &SignInResult{Challenge: challenge}, nil
+	}
+	required, err := s.mfa.IsRequired(ctx, user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		challenge, err := newMFAChallenge(user, utils.MFAEnrollmentTokenType, mfaEnrollmentTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &SignInResult{Challenge: challenge}, nil
+	}
+
+	tokens, err := s.completeSignIn(ctx, user, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return &SignInResult{Tokens: tokens}, nil
+}
+
+// CompleteMFASignIn finishes a challenged sign in with a TOTP or recovery code
+func (s *authService) CompleteMFASignIn(ctx context.Context, challengeToken, code, clientIP string) (*AuthTokens, error) {
+	user, err := s.challengeUser(ctx, challengeToken, utils.MFAChallengeTokenType, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.mfa.Verify(ctx, user, code)
+	if err != nil {
+		if errors.Is(err, ErrMFANotEnabled) {
+			// 2FA was reset since the challenge was issued; start over
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if !ok {
+		if err := s.registerFailure(ctx, user, user.Email, clientIP); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidMFACode
+	}
+	return s.completeSignIn(ctx, user, clientIP)
+}
+
+// BeginMFAEnrollment starts the enrollment a role policy demanded at sign
+// in. It applies the same guards as confirming, so a revoked token or a
+// disabled or locked account cannot replace the pending secret.
+func (s *authService) BeginMFAEnrollment(ctx context.Context, enrollmentToken, clientIP string) (*MFAEnrollment, error) {
+	user, err := s.challengeUser(ctx, enrollmentToken, utils.MFAEnrollmentTokenType, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return s.mfa.BeginEnrollment(ctx, user.UserID)
+}
+
+// CompleteMFAEnrollment confirms the enrollment and signs the user in,
+// returning the new recovery codes alongside the tokens
+func (s *authService) CompleteMFAEnrollment(ctx context.Context, enrollmentToken, code, clientIP string) (*AuthTokens, []string, error) {
+	user, err := s.challengeUser(ctx, enrollmentToken, utils.MFAEnrollmentTokenType, clientIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	codes, err := s.mfa.ConfirmEnrollment(ctx, user.UserID, code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if err := s.registerFailure(ctx, user, user.Email, clientIP); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, err
+	}
+	tokens, err := s.completeSignIn(ctx, user, clientIP)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, codes, nil
+}
+
+// challengeUser validates an MFA token and re-applies the sign-in guards,
+// since the account may have been locked or disabled in the meantime
+func (s *authService) challengeUser(ctx context.Context, token, tokenType, clientIP string) (*models.User, error) {
+	claims, err := utils.ParseToken(token, tokenType)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	user, err := s.lookupTokenUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkIPThrottle(ctx, user.Email, clientIP); err != nil {
+		return nil, err
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return nil, ErrAccountLocked
+	}
+	return user, nil
+}
+
+// completeSignIn records the successful sign in, clears failure counters and issues tokens
+func (s *authService) completeSignIn(ctx context.Context, user *models.User, clientIP string) (*AuthTokens, error) {
+	if err := s.recordAttempt(ctx, user.Email, clientIP, true); err != nil {
+		return nil, err
+	}
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if err := s.repo.ResetFailedLogins(ctx, user.UserID); err != nil {
+			return nil, err
+		}
+	}
+	return issueTokens(user)
+}
+
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*AuthTokens, error) {
+	claims, err := utils.ParseToken(refreshToken, utils.RefreshTokenType)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	user, err := s.lookupTokenUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	// a 2FA policy added after sign in takes effect when the session is refreshed
+	if !user.TOTPEnabled {
+		required, err := s.mfa.IsRequired(ctx, user.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			return nil, ErrMFAEnrollmentRequired
+		}
+	}
+	return issueTokens(user)
+}
+
+func (s *authService) Authenticate(ctx context.Context, accessToken string) (*models.User, error) {
+	claims, err := utils.ParseToken(accessToken, utils.AccessTokenType)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return s.lookupTokenUser(ctx, claims)
+}
+
+// AuthenticateAPIKey resolves an API key to its service account and the
+// permissions the key is scoped to
+func (s *authService) AuthenticateAPIKey(ctx context.Context, key string) (*models.User, []Permission, error) {
+	apiKey, err := s.apiKeys.GetActiveByHash(ctx, utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, nil, ErrInvalidToken
+		}
+		return nil, nil, err
+	}
+	if !apiKey.User.IsActive {
+		return nil, nil, ErrAccountDisabled
+	}
+	if err := s.apiKeys.TouchLastUsed(ctx, apiKey.APIKeyID, time.Now()); err != nil {
+		log.Println("⚠️ could not record api key usage:", err)
+	}
+	scopes := make([]Permission, 0)
+	for _, p := range apiKey.ScopeList() {
+		scopes = append(scopes, Permission(p))
+	}
+	return &apiKey.User, scopes, nil
+}
+
+// ForgotPassword emails a reset link. Unknown addresses are silently ignored
+// so the endpoint cannot be used to discover which emails are registered.
+func (s *authService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	token, err := issueUserToken(ctx, s.tokens, user.UserID, models.TokenPurposePasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	return sendPasswordResetMail(ctx, s.mail, user, token)
+}
+
+func (s *authService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if _, err := s.tokens.ResetPassword(ctx, utils.HashToken(token), hashed); err != nil {
+		if errors.Is(err, repositories.ErrTokenInvalid) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	if _, err := s.tokens.VerifyEmail(ctx, utils.HashToken(token)); err != nil {
+		if errors.Is(err, repositories.ErrTokenInvalid) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+	return nil
+}
+
+// ResendVerification sends a fresh verification link; like ForgotPassword it
+// does not reveal whether the address is registered or already verified
+func (s *authService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	return s.sendVerification(ctx, user)
+}
+
+func (s *authService) sendVerification(ctx context.Context, u *models.User) error {
+	token, err := issueUserToken(ctx, s.tokens, u.UserID, models.TokenPurposeEmailVerification, emailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+	return sendVerificationMail(ctx, s.mail, u, token)
+}
+
+// lookupTokenUser makes sure the user a token was issued to still exists and
+// is active, and that the token predates no password change
+func (s *authService) lookupTokenUser(ctx context.Context, claims *utils.TokenClaims) (*models.User, error) {
+	user, err := s.repo.GetByID(ctx, int64(claims.UserID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if claims.Version != user.TokenVersion {
+		return nil, ErrInvalidToken
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDisabled
+	}
 	return user, nil
 }
+
+func newMFAChallenge(user *models.User, tokenType string, ttl time.Duration) (*MFAChallenge, error) {
+	token, err := utils.GenerateToken(user.UserID, user.TokenVersion, tokenType, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &MFAChallenge{
+		MFARequired:        true,
+		EnrollmentRequired: tokenType == utils.MFAEnrollmentTokenType,
+		ChallengeToken:     token,
+		ExpiresIn:          int64(ttl.Seconds()),
+	}, nil
+}
+
+func issueTokens(user *models.User) (*AuthTokens, error) {
+	accessTTL := utils.AccessTokenTTL()
+	access, err := utils.GenerateToken(user.UserID, user.TokenVersion, utils.AccessTokenType, accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := utils.GenerateToken(user.UserID, user.TokenVersion, utils.RefreshTokenType, utils.RefreshTokenTTL())
+	if err != nil {
+		return nil, err
+	}
+	return &AuthTokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTTL.Seconds()),
+		User:         dto.NewUserResponse(user),
+	}, nil
+}
+
+// getUser loads a user by ID, mapping a missing row to ErrUserNotFound
+func getUser(ctx context.Context, repo repositories.AuthRepository, userID uint) (*models.User, error) {
+	user, err := repo.GetByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func ensureEmailAvailable(ctx context.Context, repo repositories.AuthRepository, email string) error {
+	_, err := repo.GetByEmail(ctx, email)
+	if err == nil {
+		return ErrEmailTaken
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
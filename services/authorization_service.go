@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"property-backend/repositories"
+)
+
+// AuthorizationService decides whether a user may perform an action
+type AuthorizationService interface {
+	HasPermission(ctx context.Context, userID uint, perm Permission) (bool, error)
+}
+
+type authorizationService struct {
+	repo repositories.AuthRepository
+}
+
+// NewAuthorizationService constructs an AuthorizationService
+func NewAuthorizationService(repo repositories.AuthRepository) AuthorizationService {
+	return &authorizationService{repo: repo}
+}
+
+func (s *authorizationService) HasPermission(ctx context.Context, userID uint, perm Permission) (bool, error) {
+	roles, err := s.repo.GetRoleNames(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return PermissionsForRoles(roles)[perm], nil
+}
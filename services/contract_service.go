@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	"property-backend/models"
 	"property-backend/repositories"
@@ -10,8 +11,8 @@ import (
 // ContractService defines contract domain logic
 type ContractService interface {
 	AddContract(ctx context.Context, c *models.Contract) (int64, error)
-	GetAllContracts(ctx context.Context) ([]models.Contract, error)
-	GetContractsByType(ctx context.Context, contractType string) ([]models.Contract, error)
+	GetAllContracts(ctx context.Context, page repositories.Page) ([]models.Contract, repositories.PageInfo, error)
+	GetContractsByType(ctx context.Context, contractType string, page repositories.Page) ([]models.Contract, repositories.PageInfo, error)
 }
 
 type contractService struct {
@@ -24,13 +25,19 @@ func NewContractService(repo repositories.ContractRepository) ContractService {
 }
 
 func (s *contractService) AddContract(ctx context.Context, c *models.Contract) (int64, error) {
+	if c.StartDate.IsZero() || c.EndDate.IsZero() {
+		return 0, fmt.Errorf("%w: start_date and end_date are required", ErrInvalidContractInput)
+	}
+	if !c.EndDate.After(c.StartDate) {
+		return 0, fmt.Errorf("%w: end_date must be after start_date", ErrInvalidContractInput)
+	}
 	return s.repo.Create(ctx, c)
 }
 
-func (s *contractService) GetAllContracts(ctx context.Context) ([]models.Contract, error) {
-	return s.repo.ListAll(ctx)
+func (s *contractService) GetAllContracts(ctx context.Context, page repositories.Page) ([]models.Contract, repositories.PageInfo, error) {
+	return s.repo.ListAll(ctx, page)
 }
 
-func (s *contractService) GetContractsByType(ctx context.Context, contractType string) ([]models.Contract, error) {
-	return s.repo.ListByType(ctx, contractType)
+func (s *contractService) GetContractsByType(ctx context.Context, contractType string, page repositories.Page) ([]models.Contract, repositories.PageInfo, error) {
+	return s.repo.ListByType(ctx, contractType, page)
 }
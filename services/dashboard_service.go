@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"property-backend/dto"
+	"property-backend/repositories"
+	"property-backend/utils"
+)
+
+// amcContractType is the contract_type_name of annual maintenance contracts
+const amcContractType = "amc"
+
+// DashboardService builds the portfolio dashboard
+type DashboardService interface {
+	Summary(ctx context.Context) (*dto.DashboardSummary, error)
+}
+
+type dashboardService struct {
+	repo repositories.DashboardRepository
+}
+
+// NewDashboardService constructs a DashboardService
+func NewDashboardService(repo repositories.DashboardRepository) DashboardService {
+	return &dashboardService{repo: repo}
+}
+
+func (s *dashboardService) Summary(ctx context.Context) (*dto.DashboardSummary, error) {
+	now := time.Now()
+	out := &dto.DashboardSummary{AsOf: now}
+	// agreement and contract dates are stored at midnight with inclusive end
+	// dates, so they are compared with today's date rather than the instant
+	today := utils.DateOf(now)
+	var err error
+
+	if out.ByPropertyType, err = s.repo.CountByPropertyType(ctx); err != nil {
+		return nil, err
+	}
+	for _, g := range out.ByPropertyType {
+		out.TotalProperties += g.Count
+	}
+	if out.ByDistrict, err = s.repo.CountByDistrict(ctx); err != nil {
+		return nil, err
+	}
+	if out.PortfolioValue, out.UnvaluedProperties, err = s.repo.PortfolioValue(ctx); err != nil {
+		return nil, err
+	}
+	if out.MonthlyRentalIncome, out.OccupiedProperties, err = s.repo.ActiveRent(ctx, today); err != nil {
+		return nil, err
+	}
+	out.MonthlyRentalIncome = math.Round(out.MonthlyRentalIncome*100) / 100
+	if out.TotalProperties > 0 {
+		rate := float64(out.OccupiedProperties) / float64(out.TotalProperties)
+		out.OccupancyRate = math.Round(rate*1e4) / 1e4
+	}
+	if out.ExpiringAgreements, err = s.repo.AgreementExpiries(ctx, today); err != nil {
+		return nil, err
+	}
+	if out.ExpiringContracts, err = s.repo.ContractExpiries(ctx, today, ""); err != nil {
+		return nil, err
+	}
+	if out.ExpiringAMCs, err = s.repo.ContractExpiries(ctx, today, amcContractType); err != nil {
+		return nil, err
+	}
+	if out.TaxUnpaidProperties, err = s.repo.TaxUnpaidCount(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
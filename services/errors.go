@@ -5,4 +5,62 @@ import "errors"
 var (
 	// ErrInvalidCredentials returned when signin password does not match
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAccountLocked returned when sign in is attempted on a temporarily locked account
+	ErrAccountLocked = errors.New("account temporarily locked after repeated failed sign-ins")
+	// ErrTooManyAttempts returned when a client IP has failed to sign in too many times recently
+	ErrTooManyAttempts = errors.New("too many failed sign-in attempts, try again later")
+	// ErrAccountDisabled returned when a deactivated user tries to sign in or use a token
+	ErrAccountDisabled = errors.New("account is deactivated")
+	// ErrInvalidToken returned when an access or refresh token cannot be accepted
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrUserNotFound returned when the referenced user does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrRoleNotFound returned when the referenced role does not exist or is not held by the user
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrEmailTaken returned when another user already uses the email address
+	ErrEmailTaken = errors.New("email already registered")
+	// ErrCannotDeactivateSelf returned when an administrator tries to deactivate their own account
+	ErrCannotDeactivateSelf = errors.New("you cannot deactivate your own account")
+	// ErrAPIKeyNotFound returned when an API key does not exist or is already revoked
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrNotServiceAccount returned when key management targets a regular user
+	ErrNotServiceAccount = errors.New("user is not a service account")
+	// ErrInvalidScope returned when an API key is requested with an unknown or disallowed permission
+	ErrInvalidScope = errors.New("invalid api key scope")
+	// ErrInvalidMFACode returned when a TOTP or recovery code is wrong or was already used
+	ErrInvalidMFACode = errors.New("invalid two-factor code")
+	// ErrMFAAlreadyEnabled returned when enrolling a user who already has 2FA on
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrMFANotEnabled returned when a 2FA operation needs an enrolled user
+	ErrMFANotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrMFANotEnrolling returned when confirming enrollment that was never started
+	ErrMFANotEnrolling = errors.New("two-factor enrollment has not been started")
+	// ErrMFARequiredByPolicy returned when a user's role does not allow turning 2FA off
+	ErrMFARequiredByPolicy = errors.New("two-factor authentication is required for your role")
+	// ErrMFAEnrollmentRequired returned when a session must first enroll in 2FA
+	ErrMFAEnrollmentRequired = errors.New("two-factor enrollment required")
+	// ErrPropertyNotFound returned when a property does not exist
+	ErrPropertyNotFound = errors.New("property not found")
+	// ErrInvalidPropertyInput returned when a property request fails validation
+	ErrInvalidPropertyInput = errors.New("invalid property input")
+	// ErrPropertyInUse returned when deleting a property that agreements or assets still reference
+	ErrPropertyInUse = errors.New("property is in use")
+	// ErrPropertyNotDeleted returned when purging a property that has not been deleted first
+	ErrPropertyNotDeleted = errors.New("property must be deleted before it can be purged")
+	// ErrAgreementNotFound returned when an agreement does not exist
+	ErrAgreementNotFound = errors.New("agreement not found")
+	// ErrInvalidAgreementInput returned when an agreement request fails validation
+	ErrInvalidAgreementInput = errors.New("invalid agreement input")
+	// ErrInvalidContractInput returned when a contract request fails validation
+	ErrInvalidContractInput = errors.New("invalid contract input")
+	// ErrInvalidPaymentInput returned when a rent payment request fails validation
+	ErrInvalidPaymentInput = errors.New("invalid rent payment input")
+	// ErrInvalidRentQuery returned when an overdue rent listing or effective rent request fails validation
+	ErrInvalidRentQuery = errors.New("invalid rent query")
+	// ErrInvalidAssetInput returned when an asset request fails validation
+	ErrInvalidAssetInput = errors.New("invalid asset input")
+	// ErrLastRole returned when revoking a role would leave the user with none
+	ErrLastRole = errors.New("user must keep at least one role")
+	// ErrLastAdmin returned when revoking the admin role would leave no active admin
+	ErrLastAdmin = errors.New("cannot revoke the admin role from the last active admin")
 )
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"property-backend/models"
+	"property-backend/repositories"
+)
+
+// fakeStore is an in-memory stand-in for the tables the auth, MFA and service
+// account services touch. Each fake repository embeds its interface, so a
+// test that reaches an unimplemented method panics instead of passing.
+type fakeStore struct {
+	mu            sync.Mutex
+	users         map[uint]*models.User
+	require2FA    map[uint]bool
+	recoveryCodes map[uint]map[string]bool // user -> code hash -> used
+	loginAttempts []models.LoginAttempt
+	audits        []models.AuditLog
+	apiKeys       []*models.APIKey
+}
+
+func newFakeStore(users ...models.User) *fakeStore {
+	s := &fakeStore{
+		users:         map[uint]*models.User{},
+		require2FA:    map[uint]bool{},
+		recoveryCodes: map[uint]map[string]bool{},
+	}
+	for i := range users {
+		u := users[i]
+		s.users[u.UserID] = &u
+	}
+	return s
+}
+
+// user returns a copy of the stored row, as a database read would
+func (s *fakeStore) user(id uint) (*models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	c := *u
+	return &c, nil
+}
+
+// key returns a copy of the stored key row
+func (s *fakeStore) key(id uint) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.apiKeys {
+		if k.APIKeyID == id {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (s *fakeStore) update(id uint, f func(u *models.User)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u, ok := s.users[id]; ok {
+		f(u)
+	}
+}
+
+func (s *fakeStore) auditEvents() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]string, 0, len(s.audits))
+	for _, a := range s.audits {
+		events = append(events, a.EventType)
+	}
+	return events
+}
+
+type fakeAuthRepo struct {
+	repositories.AuthRepository
+	s *fakeStore
+}
+
+func (r fakeAuthRepo) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	return r.s.user(uint(id))
+}
+
+func (r fakeAuthRepo) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.Lock()
+	var id uint
+	for _, u := range r.s.users {
+		if u.Email == email {
+			id = u.UserID
+		}
+	}
+	r.s.mu.Unlock()
+	return r.s.user(id)
+}
+
+func (r fakeAuthRepo) IncrementFailedLogins(ctx context.Context, userID uint) (int, error) {
+	var n int
+	r.s.update(userID, func(u *models.User) {
+		u.FailedLoginCount++
+		n = u.FailedLoginCount
+	})
+	return n, nil
+}
+
+func (r fakeAuthRepo) LockUntil(ctx context.Context, userID uint, until time.Time) error {
+	r.s.update(userID, func(u *models.User) { u.LockedUntil = &until })
+	return nil
+}
+
+func (r fakeAuthRepo) ResetFailedLogins(ctx context.Context, userID uint) error {
+	r.s.update(userID, func(u *models.User) {
+		u.FailedLoginCount = 0
+		u.LockedUntil = nil
+	})
+	return nil
+}
+
+type fakeRoleRepo struct {
+	repositories.RoleRepository
+	s *fakeStore
+}
+
+func (r fakeRoleRepo) UserRequires2FA(ctx context.Context, userID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.require2FA[userID], nil
+}
+
+type fakeMFARepo struct {
+	repositories.MFARepository
+	s *fakeStore
+}
+
+func (r fakeMFARepo) SetSecret(ctx context.Context, userID uint, secret string) error {
+	r.s.update(userID, func(u *models.User) {
+		if !u.TOTPEnabled {
+			u.TOTPSecret, u.TOTPLastStep = secret, 0
+		}
+	})
+	return nil
+}
+
+func (r fakeMFARepo) Enable(ctx context.Context, userID uint, step int64, codeHashes []string) error {
+	r.s.update(userID, func(u *models.User) { u.TOTPEnabled, u.TOTPLastStep = true, step })
+	return r.ReplaceRecoveryCodes(ctx, userID, codeHashes)
+}
+
+func (r fakeMFARepo) Disable(ctx context.Context, userID uint) error {
+	r.s.update(userID, func(u *models.User) { u.TOTPEnabled, u.TOTPSecret, u.TOTPLastStep = false, "", 0 })
+	r.s.mu.Lock()
+	delete(r.s.recoveryCodes, userID)
+	r.s.mu.Unlock()
+	return nil
+}
+
+func (r fakeMFARepo) UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	accepted := false
+	r.s.update(userID, func(u *models.User) {
+		if u.TOTPLastStep < step {
+			u.TOTPLastStep, accepted = step, true
+		}
+	})
+	return accepted, nil
+}
+
+func (r fakeMFARepo) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	codes := map[string]bool{}
+	for _, h := range codeHashes {
+		codes[h] = false
+	}
+	r.s.recoveryCodes[userID] = codes
+	return nil
+}
+
+func (r fakeMFARepo) ConsumeRecoveryCode(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	used, ok := r.s.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.s.recoveryCodes[userID][codeHash] = true
+	return true, nil
+}
+
+func (r fakeMFARepo) CountRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, used := range r.s.recoveryCodes[userID] {
+		if !used {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeSecurityRepo struct {
+	repositories.SecurityRepository
+	s *fakeStore
+}
+
+func (r fakeSecurityRepo) RecordLoginAttempt(ctx context.Context, a *models.LoginAttempt) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a.CreatedAt = time.Now()
+	r.s.loginAttempts = append(r.s.loginAttempts, *a)
+	return nil
+}
+
+func (r fakeSecurityRepo) CountFailedLoginsByIP(ctx context.Context, ip string, since time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	for _, a := range r.s.loginAttempts {
+		if a.IPAddress == ip && !a.Success && !a.CreatedAt.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r fakeSecurityRepo) RecordAudit(ctx context.Context, entry *models.AuditLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.audits = append(r.s.audits, *entry)
+	return nil
+}
+
+type fakeAPIKeyRepo struct {
+	repositories.APIKeyRepository
+	s *fakeStore
+}
+
+func (r fakeAPIKeyRepo) Create(ctx context.Context, k *models.APIKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	k.APIKeyID = uint(len(r.s.apiKeys) + 1)
+	c := *k
+	r.s.apiKeys = append(r.s.apiKeys, &c)
+	return nil
+}
+
+func (r fakeAPIKeyRepo) GetByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	return r.s.key(id)
+}
+
+func (r fakeAPIKeyRepo) GetActiveByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	r.s.mu.Lock()
+	var found *models.APIKey
+	for _, k := range r.s.apiKeys {
+		if k.KeyHash == keyHash && k.RevokedAt == nil {
+			c := *k
+			found = &c
+		}
+	}
+	r.s.mu.Unlock()
+	if found == nil {
+		return nil, repositories.ErrNotFound
+	}
+	user, err := r.s.user(found.UserID)
+	if err != nil {
+		return nil, err
+	}
+	found.User = *user
+	return found, nil
+}
+
+func (r fakeAPIKeyRepo) Revoke(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, k := range r.s.apiKeys {
+		if k.APIKeyID == id && k.RevokedAt == nil {
+			now := time.Now()
+			k.RevokedAt = &now
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (r fakeAPIKeyRepo) Rotate(ctx context.Context, oldID uint, replacement *models.APIKey) error {
+	if err := r.Revoke(ctx, oldID); err != nil {
+		return err
+	}
+	return r.Create(ctx, replacement)
+}
+
+func (r fakeAPIKeyRepo) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, k := range r.s.apiKeys {
+		if k.APIKeyID == id && (k.LastUsedAt == nil || k.LastUsedAt.Before(at.Add(-time.Minute))) {
+			k.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+// newFakeAuthService wires the auth and MFA services to one fake store
+func newFakeAuthService(s *fakeStore) (*authService, *mfaService) {
+	mfa := &mfaService{users: fakeAuthRepo{s: s}, roles: fakeRoleRepo{s: s}, mfa: fakeMFARepo{s: s}, security: fakeSecurityRepo{s: s}}
+	auth := &authService{repo: fakeAuthRepo{s: s}, roles: fakeRoleRepo{s: s}, security: fakeSecurityRepo{s: s}, apiKeys: fakeAPIKeyRepo{s: s}, mfa: mfa}
+	return auth, mfa
+}
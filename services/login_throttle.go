@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"property-backend/models"
+)
+
+const (
+	// every maxFailedLoginsPerAccount consecutive failures lock the account
+	maxFailedLoginsPerAccount = 5
+	// first lock lasts accountLockBase and doubles with each further lock, up to accountLockMax
+	accountLockBase = 15 * time.Minute
+	accountLockMax  = 24 * time.Hour
+
+	// a client IP with maxFailedLoginsPerIP failures inside ipFailureWindow is refused
+	maxFailedLoginsPerIP = 20
+	ipFailureWindow      = 15 * time.Minute
+)
+
+// lockDuration returns how long to lock an account after its n-th consecutive
+// failure, or zero when the failure does not trigger a lock
+func lockDuration(failures int) time.Duration {
+	if failures < maxFailedLoginsPerAccount || failures%maxFailedLoginsPerAccount != 0 {
+		return 0
+	}
+	d := accountLockBase
+	for i := maxFailedLoginsPerAccount; i < failures; i += maxFailedLoginsPerAccount {
+		d *= 2
+		if d >= accountLockMax {
+			return accountLockMax
+		}
+	}
+	return d
+}
+
+// checkIPThrottle refuses sign in from an IP that has failed too often recently
+func (s *authService) checkIPThrottle(ctx context.Context, email, ip string) error {
+	failures, err := s.security.CountFailedLoginsByIP(ctx, ip, time.Now().Add(-ipFailureWindow))
+	if err != nil {
+		return err
+	}
+	if failures < maxFailedLoginsPerIP {
+		return nil
+	}
+	if failures == maxFailedLoginsPerIP {
+		s.audit(ctx, &models.AuditLog{
+			EventType: models.AuditIPThrottled,
+			Email:     email,
+			IPAddress: ip,
+			Details:   fmt.Sprintf("%d failed sign-ins within %s", failures, ipFailureWindow),
+		})
+	}
+	return ErrTooManyAttempts
+}
+
+// registerFailure records a failed attempt and locks the account when the threshold is reached
+func (s *authService) registerFailure(ctx context.Context, user *models.User, email, ip string) error {
+	if err := s.recordAttempt(ctx, email, ip, false); err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+	failures, err := s.repo.IncrementFailedLogins(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	if d := lockDuration(failures); d > 0 {
+		until := time.Now().Add(d)
+		if err := s.repo.LockUntil(ctx, user.UserID, until); err != nil {
+			return err
+		}
+		s.audit(ctx, &models.AuditLog{
+			EventType: models.AuditAccountLocked,
+			UserID:    &user.UserID,
+			Email:     user.Email,
+			IPAddress: ip,
+			Details:   fmt.Sprintf("locked until %s after %d consecutive failed sign-ins", until.Format(time.RFC3339), failures),
+		})
+	}
+	return nil
+}
+
+func (s *authService) recordAttempt(ctx context.Context, email, ip string, success bool) error {
+	return s.security.RecordLoginAttempt(ctx, &models.LoginAttempt{Email: email, IPAddress: ip, Success: success})
+}
+
+// audit writes an audit record; failures are logged rather than failing the request
+func (s *authService) audit(ctx context.Context, entry *models.AuditLog) {
+	if err := s.security.RecordAudit(ctx, entry); err != nil {
+		log.Println("⚠️ could not write audit log:", err)
+	}
+}
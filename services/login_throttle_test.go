@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"property-backend/models"
+)
+
+func TestLockDuration(t *testing.T) {
+	for _, tc := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{4, 0},
+		{5, 15 * time.Minute},
+		{6, 0},
+		{9, 0},
+		{10, 30 * time.Minute},
+		{15, time.Hour},
+		{20, 2 * time.Hour},
+		{25, 4 * time.Hour},
+		{30, 8 * time.Hour},
+		{35, 16 * time.Hour},
+		{40, 24 * time.Hour},
+		{45, 24 * time.Hour},
+		{41, 0},
+		{500, 24 * time.Hour},
+	} {
+		if got := lockDuration(tc.failures); got != tc.want {
+			t.Errorf("lockDuration(%d) = %s, want %s", tc.failures, got, tc.want)
+		}
+	}
+}
+
+func TestIPThrottleWindow(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore()
+	auth, _ := newFakeAuthService(store)
+	const ip = "10.0.0.9"
+
+	// failures that fell out of the window no longer count
+	for i := 0; i < maxFailedLoginsPerIP; i++ {
+		store.loginAttempts = append(store.loginAttempts, models.LoginAttempt{IPAddress: ip, CreatedAt: time.Now().Add(-ipFailureWindow - time.Minute)})
+	}
+	for i := 0; i < maxFailedLoginsPerIP-1; i++ {
+		if err := auth.registerFailure(ctx, nil, "nobody@example.com", ip); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := auth.checkIPThrottle(ctx, "nobody@example.com", ip); err != nil {
+		t.Fatalf("%d recent failures: %v, want nil", maxFailedLoginsPerIP-1, err)
+	}
+	if err := auth.registerFailure(ctx, nil, "nobody@example.com", ip); err != nil {
+		t.Fatal(err)
+	}
+	if err := auth.checkIPThrottle(ctx, "nobody@example.com", ip); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("%d recent failures: %v, want ErrTooManyAttempts", maxFailedLoginsPerIP, err)
+	}
+	if err := auth.checkIPThrottle(ctx, "nobody@example.com", "10.0.0.10"); err != nil {
+		t.Errorf("another IP: %v, want nil", err)
+	}
+	if events := store.auditEvents(); len(events) != 1 || events[0] != models.AuditIPThrottled {
+		t.Errorf("audit events %v, want one IP throttle", events)
+	}
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"property-backend/models"
+	"property-backend/repositories"
+	"property-backend/utils"
+)
+
+const (
+	// challenge tokens bridge the password and code steps of sign in
+	mfaChallengeTTL = 5 * time.Minute
+	// enrollment tokens leave time to scan the QR code and confirm a first code
+	mfaEnrollmentTTL = 15 * time.Minute
+
+	recoveryCodeCount = 10
+	defaultMFAIssuer  = "Property Backend"
+)
+
+// MFAEnrollment is the material an authenticator app needs; the secret is
+// shown once while enrollment is pending
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAStatus describes a user's two-factor settings
+type MFAStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+// MFAService manages TOTP enrollment, recovery codes and the per-role 2FA policy
+type MFAService interface {
+	Status(ctx context.Context, userID uint) (*MFAStatus, error)
+	BeginEnrollment(ctx context.Context, userID uint) (*MFAEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, userID uint, code string) ([]string, error)
+	Verify(ctx context.Context, user *models.User, code string) (bool, error)
+	Disable(ctx context.Context, userID uint, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error)
+	Reset(ctx context.Context, actorID, userID uint) error
+	IsRequired(ctx context.Context, userID uint) (bool, error)
+	SetRolePolicy(ctx context.Context, actorID, roleID uint, require bool) (*models.RolesMaster, error)
+}
+
+type mfaService struct {
+	users    repositories.AuthRepository
+	roles    repositories.RoleRepository
+	mfa      repositories.MFARepository
+	security repositories.SecurityRepository
+}
+
+// NewMFAService constructs an MFAService
+func NewMFAService(users repositories.AuthRepository, roles repositories.RoleRepository, mfa repositories.MFARepository, security repositories.SecurityRepository) MFAService {
+	return &mfaService{users: users, roles: roles, mfa: mfa, security: security}
+}
+
+func (s *mfaService) Status(ctx context.Context, userID uint) (*MFAStatus, error) {
+	user, err := getUser(ctx, s.users, userID)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.roles.UserRequires2FA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &MFAStatus{Enabled: user.TOTPEnabled, Required: required}
+	if user.TOTPEnabled {
+		if status.RecoveryCodesRemaining, err = s.mfa.CountRecoveryCodes(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// BeginEnrollment generates a fresh secret. Calling it again before
+// confirming simply replaces the pending secret.
+func (s *mfaService) BeginEnrollment(ctx context.Context, userID uint) (*MFAEnrollment, error) {
+	user, err := getUser(ctx, s.users, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfa.SetSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(mfaIssuer(), user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment turns 2FA on once the user proves their app produces
+// valid codes, and returns the plaintext recovery codes (shown only once)
+func (s *mfaService) ConfirmEnrollment(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := getUser(ctx, s.users, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolling
+	}
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, normalizeMFACode(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfa.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, &models.AuditLog{EventType: models.AuditMFAEnabled, UserID: &user.UserID, ActorUserID: &user.UserID, Email: user.Email})
+	return codes, nil
+}
+
+// Verify accepts either a current TOTP code or an unused recovery code
+func (s *mfaService) Verify(ctx context.Context, user *models.User, code string) (bool, error) {
+	if !user.TOTPEnabled {
+		return false, ErrMFANotEnabled
+	}
+	code = normalizeMFACode(code)
+	if step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		return s.mfa.UseTOTPStep(ctx, user.UserID, step)
+	}
+	used, err := s.mfa.ConsumeRecoveryCode(ctx, user.UserID, utils.HashToken(code))
+	if err != nil || !used {
+		return false, err
+	}
+	s.audit(ctx, &models.AuditLog{EventType: models.AuditMFARecoveryCodeUsed, UserID: &user.UserID, Email: user.Email})
+	return true, nil
+}
+
+// Disable turns 2FA off for the caller after re-checking password and code.
+// Users whose roles require 2FA cannot opt out.
+func (s *mfaService) Disable(ctx context.Context, userID uint, password, code string) error {
+	user, err := getUser(ctx, s.users, userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(password, user.HashedPassword) {
+		return ErrInvalidCredentials
+	}
+	required, err := s.roles.UserRequires2FA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequiredByPolicy
+	}
+	ok, err := s.Verify(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	if err := s.mfa.Disable(ctx, userID); err != nil {
+		return err
+	}
+	s.audit(ctx, &models.AuditLog{EventType: models.AuditMFADisabled, UserID: &user.UserID, ActorUserID: &user.UserID, Email: user.Email})
+	return nil
+}
+
+// RegenerateRecoveryCodes invalidates all existing recovery codes and issues new ones
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := getUser(ctx, s.users, userID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.Verify(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfa.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset lets an administrator remove 2FA from a user who lost their device.
+// If a role policy applies, the user is asked to enroll again at next sign in.
+func (s *mfaService) Reset(ctx context.Context, actorID, userID uint) error {
+	user, err := getUser(ctx, s.users, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.mfa.Disable(ctx, userID); err != nil {
+		return err
+	}
+	s.audit(ctx, &models.AuditLog{EventType: models.AuditMFAReset, UserID: &user.UserID, ActorUserID: &actorID, Email: user.Email})
+	return nil
+}
+
+func (s *mfaService) IsRequired(ctx context.Context, userID uint) (bool, error) {
+	return s.roles.UserRequires2FA(ctx, userID)
+}
+
+// SetRolePolicy turns the 2FA requirement for a role on or off
+func (s *mfaService) SetRolePolicy(ctx context.Context, actorID, roleID uint, require bool) (*models.RolesMaster, error) {
+	if err := s.roles.SetRequire2FA(ctx, roleID, require); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	role, err := s.roles.GetByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, &models.AuditLog{
+		EventType:   models.AuditMFAPolicyChanged,
+		ActorUserID: &actorID,
+		Details:     fmt.Sprintf("role %s require_2fa=%t", role.Role, require),
+	})
+	return role, nil
+}
+
+// audit writes an audit record; failures are logged rather than failing the request
+func (s *mfaService) audit(ctx context.Context, entry *models.AuditLog) {
+	if err := s.security.RecordAudit(ctx, entry); err != nil {
+		log.Println("⚠️ could not write audit log:", err)
+	}
+}
+
+// newRecoveryCodes returns plaintext codes formatted as xxxx-xxxx and their hashes
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := utils.RandomToken(4)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+		hashes = append(hashes, utils.HashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeMFACode drops the separators users tend to type so "1234 56" and
+// "ab12-cd34" match
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(code)))
+}
+
+// mfaIssuer names the account in authenticator apps (MFA_ISSUER)
+func mfaIssuer() string {
+	if v := os.Getenv("MFA_ISSUER"); v != "" {
+		return v
+	}
+	return defaultMFAIssuer
+}
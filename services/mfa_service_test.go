@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"property-backend/models"
+	"property-backend/utils"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// totpAt computes the code an authenticator app shows steps periods from now
+func totpAt(t *testing.T, secret string, steps int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(time.Now().Unix()/30+steps))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+func TestMFAEnrollmentIssuesHashedRecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore(models.User{UserID: 1, Email: "asha@example.com", IsActive: true})
+	_, mfa := newFakeAuthService(store)
+
+	if _, err := mfa.ConfirmEnrollment(ctx, 1, "123456"); !errors.Is(err, ErrMFANotEnrolling) {
+		t.Fatalf("confirm before enrolling: %v, want ErrMFANotEnrolling", err)
+	}
+	enrollment, err := mfa.BeginEnrollment(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(enrollment.ProvisioningURI, "secret="+enrollment.Secret) {
+		t.Errorf("provisioning URI %q does not carry the secret", enrollment.ProvisioningURI)
+	}
+	if _, err := mfa.ConfirmEnrollment(ctx, 1, "000000"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("confirm with a wrong code: %v, want ErrInvalidMFACode", err)
+	}
+	if u, _ := store.user(1); u.TOTPEnabled {
+		t.Fatal("2FA enabled by a wrong code")
+	}
+
+	codes, err := mfa.ConfirmEnrollment(ctx, 1, totpAt(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("%d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+	format := regexp.MustCompile(`^[0-9a-f]{4}-[0-9a-f]{4}$`)
+	stored := store.recoveryCodes[1]
+	for _, c := range codes {
+		if !format.MatchString(c) {
+			t.Errorf("recovery code %q is not xxxx-xxxx", c)
+		}
+		if _, ok := stored[c]; ok {
+			t.Errorf("recovery code %q stored in plaintext", c)
+		}
+		if _, ok := stored[utils.HashToken(strings.ReplaceAll(c, "-", ""))]; !ok {
+			t.Errorf("recovery code %q has no stored hash", c)
+		}
+	}
+	status, err := mfa.Status(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Enabled || status.RecoveryCodesRemaining != recoveryCodeCount {
+		t.Errorf("status %+v after enrollment", status)
+	}
+	if _, err := mfa.BeginEnrollment(ctx, 1); !errors.Is(err, ErrMFAAlreadyEnabled) {
+		t.Errorf("re-enrolling: %v, want ErrMFAAlreadyEnabled", err)
+	}
+}
+
+func TestMFAVerifyRefusesReplays(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore(models.User{UserID: 1, Email: "asha@example.com", IsActive: true, TOTPSecret: testTOTPSecret, TOTPEnabled: true})
+	_, mfa := newFakeAuthService(store)
+	if err := (fakeMFARepo{s: store}).ReplaceRecoveryCodes(ctx, 1, []string{utils.HashToken("aaaa1111"), utils.HashToken("bbbb2222")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the cases run in order against the same user
+	for _, tc := range []struct {
+		name string
+		code string
+		ok   bool
+	}{
+		{"current code", totpAt(t, testTOTPSecret, 0), true},
+		{"same code again", totpAt(t, testTOTPSecret, 0), false},
+		{"earlier step after a later one", totpAt(t, testTOTPSecret, -1), false},
+		{"next step", totpAt(t, testTOTPSecret, 1), true},
+		{"code outside the skew window", totpAt(t, testTOTPSecret, 3), false},
+		{"recovery code with separator and capitals", "AAAA-1111", true},
+		{"recovery code used twice", "aaaa1111", false},
+		{"recovery code with spaces", " bbbb 2222 ", true},
+		{"unknown recovery code", "cccc3333", false},
+	} {
+		u, _ := store.user(1)
+		ok, err := mfa.Verify(ctx, u, tc.code)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if ok != tc.ok {
+			t.Errorf("%s: accepted=%t, want %t", tc.name, ok, tc.ok)
+		}
+	}
+	if n, _ := (fakeMFARepo{s: store}).CountRecoveryCodes(ctx, 1); n != 0 {
+		t.Errorf("%d recovery codes left, want 0", n)
+	}
+	if events := store.auditEvents(); len(events) != 2 || events[0] != models.AuditMFARecoveryCodeUsed {
+		t.Errorf("audit events %v, want two recovery code uses", events)
+	}
+
+	store.update(1, func(u *models.User) { u.TOTPEnabled = false })
+	u, _ := store.user(1)
+	if _, err := mfa.Verify(ctx, u, totpAt(t, testTOTPSecret, 0)); !errors.Is(err, ErrMFANotEnabled) {
+		t.Errorf("verify without 2FA: %v, want ErrMFANotEnabled", err)
+	}
+}
+
+// mfaUser is an active user with a password and 2FA turned on
+func mfaUser(t *testing.T) models.User {
+	t.Helper()
+	hashed, err := utils.HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.User{UserID: 1, Email: "asha@example.com", HashedPassword: hashed, IsActive: true, TOTPSecret: testTOTPSecret, TOTPEnabled: true}
+}
+
+func TestSignInWithMFAChallenge(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	ctx := context.Background()
+	store := newFakeStore(mfaUser(t))
+	auth, _ := newFakeAuthService(store)
+
+	result, err := auth.SignIn(ctx, "asha@example.com", "correct horse", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tokens != nil || result.Challenge == nil || !result.Challenge.MFARequired || result.Challenge.EnrollmentRequired {
+		t.Fatalf("sign in with 2FA returned %+v, want only a code challenge", result)
+	}
+	challenge := result.Challenge.ChallengeToken
+	if _, err := auth.Authenticate(ctx, challenge); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("challenge token used as access token: %v, want ErrInvalidToken", err)
+	}
+	if _, err := auth.BeginMFAEnrollment(ctx, challenge, "10.0.0.1"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("code challenge used for enrollment: %v, want ErrInvalidToken", err)
+	}
+
+	if _, err := auth.CompleteMFASignIn(ctx, challenge, "000000", "10.0.0.1"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("wrong code: %v, want ErrInvalidMFACode", err)
+	}
+	if u, _ := store.user(1); u.FailedLoginCount != 1 {
+		t.Errorf("failed login count %d after a wrong code, want 1", u.FailedLoginCount)
+	}
+
+	code := totpAt(t, testTOTPSecret, 0)
+	tokens, err := auth.CompleteMFASignIn(ctx, challenge, code, "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens.AccessToken == "" || tokens.User.Email != "asha@example.com" {
+		t.Errorf("tokens %+v", tokens)
+	}
+	if u, _ := store.user(1); u.FailedLoginCount != 0 {
+		t.Errorf("failed login count %d after signing in, want 0", u.FailedLoginCount)
+	}
+	if _, err := auth.CompleteMFASignIn(ctx, challenge, code, "10.0.0.1"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("replayed code: %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestSignInWithRequiredEnrollment(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	ctx := context.Background()
+	u := mfaUser(t)
+	u.TOTPSecret, u.TOTPEnabled = "", false
+	store := newFakeStore(u)
+	store.require2FA[1] = true
+	auth, _ := newFakeAuthService(store)
+
+	result, err := auth.SignIn(ctx, "asha@example.com", "correct horse", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tokens != nil || result.Challenge == nil || !result.Challenge.EnrollmentRequired {
+		t.Fatalf("sign in under a 2FA policy returned %+v, want an enrollment challenge", result)
+	}
+	token := result.Challenge.ChallengeToken
+	if _, err := auth.CompleteMFASignIn(ctx, token, "000000", "10.0.0.1"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("enrollment token used as code challenge: %v, want ErrInvalidToken", err)
+	}
+	enrollment, err := auth.BeginMFAEnrollment(ctx, token, "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, codes, err := auth.CompleteMFAEnrollment(ctx, token, totpAt(t, enrollment.Secret, 0), "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens.AccessToken == "" || len(codes) != recoveryCodeCount {
+		t.Errorf("enrollment returned tokens %+v and %d codes", tokens, len(codes))
+	}
+	if u, _ := store.user(1); !u.TOTPEnabled {
+		t.Error("2FA not enabled after enrollment")
+	}
+}
+
+func TestBeginMFAEnrollmentRechecksAccount(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name   string
+		change func(u *models.User)
+		want   error
+	}{
+		{"token revoked by a password change", func(u *models.User) { u.TokenVersion++ }, ErrInvalidToken},
+		{"account deactivated", func(u *models.User) { u.IsActive = false }, ErrAccountDisabled},
+		{"account locked", func(u *models.User) { until := time.Now().Add(time.Hour); u.LockedUntil = &until }, ErrAccountLocked},
+	} {
+		u := mfaUser(t)
+		u.TOTPSecret, u.TOTPEnabled = "PENDINGSECRET", false
+		store := newFakeStore(u)
+		store.require2FA[1] = true
+		auth, _ := newFakeAuthService(store)
+		result, err := auth.SignIn(ctx, "asha@example.com", "correct horse", "10.0.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.update(1, tc.change)
+		if _, err := auth.BeginMFAEnrollment(ctx, result.Challenge.ChallengeToken, "10.0.0.1"); !errors.Is(err, tc.want) {
+			t.Errorf("%s: %v, want %v", tc.name, err, tc.want)
+		}
+		if u, _ := store.user(1); u.TOTPSecret != "PENDINGSECRET" {
+			t.Errorf("%s: pending secret replaced", tc.name)
+		}
+	}
+}
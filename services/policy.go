@@ -0,0 +1,87 @@
+package services
+
+import (
+	"slices"
+
+	"property-backend/models"
+)
+
+// Permission names an action a caller may perform on a resource
+type Permission string
+
+const (
+	PermPropertiesRead  Permission = "properties:read"
+	PermPropertiesWrite Permission = "properties:write"
+	PermPropertiesPurge Permission = "properties:purge"
+	PermAgreementsRead  Permission = "agreements:read"
+	PermAgreementsWrite Permission = "agreements:write"
+	PermAssetsRead      Permission = "assets:read"
+	PermAssetsWrite     Permission = "assets:write"
+	PermContractsRead   Permission = "contracts:read"
+	PermContractsWrite  Permission = "contracts:write"
+	PermUsersManage     Permission = "users:manage"
+	// PermRentWrite records rent payments without allowing agreements to be edited
+	PermRentWrite Permission = "rent:write"
+)
+
+// apiKeyPermissions are the permissions an API key may be scoped to.
+// User administration and purging are deliberately left out: keys never
+// manage users or destroy data permanently.
+var apiKeyPermissions = []Permission{
+	PermPropertiesRead, PermPropertiesWrite,
+	PermAgreementsRead, PermAgreementsWrite,
+	PermAssetsRead, PermAssetsWrite,
+	PermContractsRead, PermContractsWrite,
+	PermRentWrite,
+}
+
+var readOnlyPermissions = []Permission{
+	PermPropertiesRead,
+	PermAgreementsRead,
+	PermAssetsRead,
+	PermContractsRead,
+}
+
+// rolePermissions is the policy: which permissions each built-in role grants.
+// Roles in roles_master that are not listed here grant nothing.
+var rolePermissions = map[string][]Permission{
+	models.RoleAdmin: {
+		PermPropertiesRead, PermPropertiesWrite, PermPropertiesPurge,
+		PermAgreementsRead, PermAgreementsWrite,
+		PermAssetsRead, PermAssetsWrite,
+		PermContractsRead, PermContractsWrite,
+		PermRentWrite,
+		PermUsersManage,
+	},
+	models.RolePropertyManager: {
+		PermPropertiesRead, PermPropertiesWrite,
+		PermAgreementsRead, PermAgreementsWrite,
+		PermAssetsRead, PermAssetsWrite,
+		PermContractsRead, PermContractsWrite,
+		PermRentWrite,
+	},
+	// accountants see everything and record rent received
+	models.RoleAccountant: append(slices.Clone(readOnlyPermissions), PermRentWrite),
+	models.RoleViewer:     readOnlyPermissions,
+}
+
+// PermissionsForRoles returns the union of permissions granted by the given roles
+func PermissionsForRoles(roles []string) map[Permission]bool {
+	perms := make(map[Permission]bool)
+	for _, role := range roles {
+		for _, p := range rolePermissions[role] {
+			perms[p] = true
+		}
+	}
+	return perms
+}
+
+// IsAPIKeyScope reports whether an API key may be granted the permission
+func IsAPIKeyScope(p Permission) bool {
+	for _, allowed := range apiKeyPermissions {
+		if p == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -2,40 +2,305 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"property-backend/dto"
 	"property-backend/models"
 	"property-backend/repositories"
+	"property-backend/utils"
 )
 
 // PropertyService defines property domain logic
 type PropertyService interface {
 	Total(ctx context.Context) (int64, error)
-	ActiveRentalCount(ctx context.Context) (int64, error)
-	AddProperty(ctx context.Context, req interface{}) (int64, error)
-	ListByType(ctx context.Context, propertyType string) ([]models.Property, error)
+	OccupancyCounts(ctx context.Context) (map[string]int64, error)
+	AddProperty(ctx context.Context, userID uint, in *dto.PropertyCreate) (int64, error)
+	SearchProperties(ctx context.Context, filter repositories.PropertyFilter, page repositories.Page) ([]models.Property, repositories.PageInfo, error)
+	GetProperty(ctx context.Context, id uint) (*models.Property, error)
+	UpdateProperty(ctx context.Context, actorID, id uint, upd *dto.PropertyUpdate, replace bool) (*models.Property, error)
+	DeleteProperty(ctx context.Context, id uint) error
+	RestoreProperty(ctx context.Context, id uint) (*models.Property, error)
+	PurgeProperty(ctx context.Context, id uint) error
+	AddValuation(ctx context.Context, v *models.PropertyValuation) error
+	ListValuations(ctx context.Context, propertyID uint, page repositories.Page) ([]models.PropertyValuation, repositories.PageInfo, error)
 }
 
 type propertyService struct {
-	repo repositories.PropertyRepository
+	repo       repositories.PropertyRepository
+	valuations repositories.ValuationRepository
 }
 
 // NewPropertyService constructs a PropertyService
-func NewPropertyService(repo repositories.PropertyRepository) PropertyService {
-	return &propertyService{repo: repo}
+func NewPropertyService(repo repositories.PropertyRepository, valuations repositories.ValuationRepository) PropertyService {
+	return &propertyService{repo: repo, valuations: valuations}
 }
 
 func (s *propertyService) Total(ctx context.Context) (int64, error) {
 	return s.repo.Total(ctx)
 }
 
-func (s *propertyService) ActiveRentalCount(ctx context.Context) (int64, error) {
-	return s.repo.ActiveRentalCount(ctx)
+// OccupancyCounts counts properties by current occupancy status
+func (s *propertyService) OccupancyCounts(ctx context.Context) (map[string]int64, error) {
+	return s.repo.OccupancyCounts(ctx)
 }
 
-func (s *propertyService) AddProperty(ctx context.Context, req interface{}) (int64, error) {
-	return s.repo.Create(ctx, req)
+// AddProperty creates a property owned by userID
+func (s *propertyService) AddProperty(ctx context.Context, userID uint, in *dto.PropertyCreate) (int64, error) {
+	if err := validatePropertyCreate(in); err != nil {
+		return 0, err
+	}
+	return s.repo.Create(ctx, userID, in)
 }
 
-func (s *propertyService) ListByType(ctx context.Context, propertyType string) ([]models.Property, error) {
-	return s.repo.ListByType(ctx, propertyType)
+func validatePropertyCreate(in *dto.PropertyCreate) error {
+	if in.PropertyTypeID != 0 && in.PropertyTypeName != "" {
+		return fmt.Errorf("%w: give either property_type_id or property_type_name", ErrInvalidPropertyInput)
+	}
+	if in.Address.HasLocation() && !in.Address.HasFullLocation() {
+		return fmt.Errorf("%w: country_name, state_name, district_name and taluk_name must be given together", ErrInvalidPropertyInput)
+	}
+	// without a location the address cannot be stored, so refuse rather than drop it
+	if !in.Address.HasLocation() && !in.Address.IsEmpty() {
+		return fmt.Errorf("%w: %v", ErrInvalidPropertyInput, repositories.ErrAddressLocationRequired)
+	}
+	if err := checkUnitCount(in.UnitCount); err != nil {
+		return err
+	}
+	l := in.Land
+	if err := checkNonNegative(in.Value, in.Income, l.Acre, l.Gunte, l.Karab, in.Building.PlotSize, in.Building.BuiltUpArea); err != nil {
+		return err
+	}
+	return checkKarab(l.Acre, l.Gunte, l.Karab)
+}
+
+// checkUnitCount rejects a unit count below one; nil means let as a whole
+func checkUnitCount(n *int) error {
+	if n != nil && *n < 1 {
+		return fmt.Errorf("%w: unit_count must be at least 1", ErrInvalidPropertyInput)
+	}
+	return nil
+}
+
+// checkKarab rejects a karab (unusable) portion larger than the land extent
+func checkKarab(acre, gunte, karab *float64) error {
+	if karab == nil || (acre == nil && gunte == nil) {
+		return nil
+	}
+	var extent float64 // in gunta
+	if acre != nil {
+		extent += *acre * 40
+	}
+	if gunte != nil {
+		extent += *gunte
+	}
+	if *karab > extent {
+		return fmt.Errorf("%w: karab (%v gunta) exceeds the land extent (%v gunta)", ErrInvalidPropertyInput, *karab, extent)
+	}
+	return nil
+}
+
+// checkNonNegative rejects negative amounts and areas; nil means not given
+func checkNonNegative(value, income *models.Money, acre, gunte, karab, plotSize, builtUpArea *float64) error {
+	for name, m := range map[string]*models.Money{"value": value, "income": income} {
+		if m != nil && *m < 0 {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidPropertyInput, name)
+		}
+	}
+	for name, f := range map[string]*float64{"acre": acre, "gunte": gunte, "karab": karab, "plot_size": plotSize, "built_up_area": builtUpArea} {
+		if f != nil && *f < 0 {
+			return fmt.Errorf("%w: %s must not be negative", ErrInvalidPropertyInput, name)
+		}
+	}
+	return nil
+}
+
+func (s *propertyService) SearchProperties(ctx context.Context, filter repositories.PropertyFilter, page repositories.Page) ([]models.Property, repositories.PageInfo, error) {
+	if filter.MinValue != nil && filter.MaxValue != nil && *filter.MinValue > *filter.MaxValue {
+		return nil, repositories.PageInfo{}, fmt.Errorf("%w: min_value is greater than max_value", ErrInvalidPropertyInput)
+	}
+	for _, status := range filter.Occupancy {
+		if !slices.Contains(models.OccupancyStatuses, status) {
+			return nil, repositories.PageInfo{}, fmt.Errorf("%w: unknown occupancy %q, expected one of %s",
+				ErrInvalidPropertyInput, status, strings.Join(models.OccupancyStatuses, ", "))
+		}
+	}
+	props, info, err := s.repo.Search(ctx, filter, page)
+	switch {
+	case errors.Is(err, repositories.ErrInvalidSort):
+		return nil, info, fmt.Errorf("%w: unknown sort field %q", ErrInvalidPropertyInput, filter.Sort)
+	case errors.Is(err, repositories.ErrCursorWithSort):
+		return nil, info, fmt.Errorf("%w: cursor cannot be combined with sort=%s, use offset", ErrInvalidPropertyInput, filter.Sort)
+	}
+	return props, info, err
+}
+
+func (s *propertyService) GetProperty(ctx context.Context, id uint) (*models.Property, error) {
+	prop, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrPropertyNotFound
+		}
+		return nil, err
+	}
+	return prop, nil
+}
+
+// UpdateProperty applies a PUT (replace=true) or PATCH and returns the updated record
+func (s *propertyService) UpdateProperty(ctx context.Context, actorID, id uint, upd *dto.PropertyUpdate, replace bool) (*models.Property, error) {
+	if err := validatePropertyUpdate(upd, replace); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, actorID, id, upd, replace); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			return nil, ErrPropertyNotFound
+		case errors.Is(err, repositories.ErrAddressLocationRequired), errors.Is(err, repositories.ErrKarabExceedsExtent):
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPropertyInput, err)
+		}
+		return nil, err
+	}
+	return s.GetProperty(ctx, id)
+}
+
+func validatePropertyUpdate(upd *dto.PropertyUpdate, replace bool) error {
+	if upd.PropertyTypeID != nil && upd.PropertyTypeName != nil {
+		return fmt.Errorf("%w: give either property_type_id or property_type_name", ErrInvalidPropertyInput)
+	}
+	if upd.PropertyTypeName != nil && *upd.PropertyTypeName == "" {
+		return fmt.Errorf("%w: property_type_name must not be empty", ErrInvalidPropertyInput)
+	}
+	if upd.Address != nil && upd.Address.HasLocation() && !upd.Address.HasFullLocation() {
+		return fmt.Errorf("%w: country_name, state_name, district_name and taluk_name must be given together", ErrInvalidPropertyInput)
+	}
+	if err := checkUnitCount(upd.UnitCount); err != nil {
+		return err
+	}
+	land, building := upd.Land, upd.Building
+	if land == nil {
+		land = &dto.LandDetailsUpdate{}
+	}
+	if building == nil {
+		building = &dto.BuildingDetailsUpdate{}
+	}
+	if err := checkNonNegative(upd.Value, upd.Income, land.Acre, land.Gunte, land.Karab, building.PlotSize, building.BuiltUpArea); err != nil {
+		return err
+	}
+	if !replace {
+		return nil
+	}
+	if err := checkKarab(land.Acre, land.Gunte, land.Karab); err != nil {
+		return err
+	}
+	// a full replacement must describe a complete property
+	if upd.PropertyName == nil || *upd.PropertyName == "" {
+		return fmt.Errorf("%w: property_name is required", ErrInvalidPropertyInput)
+	}
+	if upd.PropertyTypeID == nil && upd.PropertyTypeName == nil {
+		return fmt.Errorf("%w: property_type_id or property_type_name is required", ErrInvalidPropertyInput)
+	}
+	if upd.Address == nil || !upd.Address.HasFullLocation() {
+		return fmt.Errorf("%w: address with country_name, state_name, district_name and taluk_name is required", ErrInvalidPropertyInput)
+	}
+	return nil
+}
+
+// DeleteProperty soft-deletes a property that no current agreement or asset uses
+func (s *propertyService) DeleteProperty(ctx context.Context, id uint) error {
+	return s.mapDeleteError(ctx, id, s.repo.Delete(ctx, id), true)
+}
+
+func (s *propertyService) RestoreProperty(ctx context.Context, id uint) (*models.Property, error) {
+	if err := s.repo.Restore(ctx, id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrPropertyNotFound
+		}
+		return nil, err
+	}
+	return s.GetProperty(ctx, id)
+}
+
+// PurgeProperty permanently removes a soft-deleted property
+func (s *propertyService) PurgeProperty(ctx context.Context, id uint) error {
+	return s.mapDeleteError(ctx, id, s.repo.Purge(ctx, id), false)
+}
+
+// mapDeleteError translates repository errors, spelling out what still
+// references the property when it is in use
+func (s *propertyService) mapDeleteError(ctx context.Context, id uint, err error, activeOnly bool) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repositories.ErrNotFound):
+		return ErrPropertyNotFound
+	case errors.Is(err, repositories.ErrPropertyNotDeleted):
+		return ErrPropertyNotDeleted
+	case errors.Is(err, repositories.ErrPropertyInUse):
+		agreements, assets, countErr := s.repo.References(ctx, id, activeOnly)
+		if countErr != nil {
+			return ErrPropertyInUse
+		}
+		return fmt.Errorf("%w: %d agreement(s) and %d asset(s) reference it", ErrPropertyInUse, agreements, assets)
+	}
+	return err
+}
+
+// SetAreaSummary fills p.Area with the property's areas in the given unit,
+// using the canonical land areas and the building areas (stored in square
+// feet). Sections that are not loaded are left out.
+func SetAreaSummary(p *models.Property, unit utils.AreaUnit) {
+	convert := func(v *float64, from utils.AreaUnit) *float64 {
+		if v == nil {
+			return nil
+		}
+		out := utils.FromSquareMetres(utils.ToSquareMetres(*v, from), unit)
+		return &out
+	}
+	area := &models.AreaSummary{Unit: string(unit)}
+	if l := p.LandDetails; l != nil {
+		area.Total = convert(l.TotalAreaSqm, utils.AreaSquareMetre)
+		area.Karab = convert(l.Karab, utils.AreaGunta)
+		area.Usable = convert(l.UsableAreaSqm, utils.AreaSquareMetre)
+	}
+	if b := p.BuildingDetails; b != nil {
+		area.PlotSize = convert(b.PlotSize, utils.AreaSquareFeet)
+		area.BuiltUpArea = convert(b.BuiltUpArea, utils.AreaSquareFeet)
+	}
+	p.Area = area
+}
+
+// AddValuation records a valuation; the property's value becomes that of its
+// latest valuation
+func (s *propertyService) AddValuation(ctx context.Context, v *models.PropertyValuation) error {
+	if v.Amount < 0 {
+		return fmt.Errorf("%w: amount must not be negative", ErrInvalidPropertyInput)
+	}
+	if !slices.Contains(models.ValuationSources, v.Source) {
+		return fmt.Errorf("%w: source must be one of %s", ErrInvalidPropertyInput, strings.Join(models.ValuationSources, ", "))
+	}
+	if v.ValuedOn.After(time.Now()) {
+		return fmt.Errorf("%w: valued_on must not be in the future", ErrInvalidPropertyInput)
+	}
+	if err := s.valuations.Create(ctx, v); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrPropertyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListValuations returns a property's valuation history, newest first
+func (s *propertyService) ListValuations(ctx context.Context, propertyID uint, page repositories.Page) ([]models.PropertyValuation, repositories.PageInfo, error) {
+	valuations, info, err := s.valuations.ListByProperty(ctx, propertyID, page)
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return nil, info, ErrPropertyNotFound
+	case errors.Is(err, repositories.ErrCursorUnsupported):
+		return nil, info, fmt.Errorf("%w: %v", ErrInvalidPropertyInput, err)
+	}
+	return valuations, info, err
 }
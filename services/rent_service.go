@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"property-backend/dto"
+	"property-backend/models"
+	"property-backend/repositories"
+	"property-backend/utils"
+)
+
+// RentService defines rent schedule, payment and arrears logic
+type RentService interface {
+	Schedule(ctx context.Context, agreementID uint) ([]dto.ScheduleLine, error)
+	Ledger(ctx context.Context, agreementID uint) (*dto.RentLedger, error)
+	AddPayment(ctx context.Context, p *models.RentPayment) error
+	Overdue(ctx context.Context, page repositories.Page) ([]dto.OverdueRent, repositories.PageInfo, error)
+	EffectiveRent(ctx context.Context, agreementID uint, on time.Time) (*dto.EffectiveRent, error)
+}
+
+type rentService struct {
+	repo repositories.RentRepository
+}
+
+// NewRentService constructs a RentService
+func NewRentService(repo repositories.RentRepository) RentService {
+	return &rentService{repo: repo}
+}
+
+// Schedule returns an agreement's billing periods with payments applied to
+// the oldest rent first
+func (s *rentService) Schedule(ctx context.Context, agreementID uint) ([]dto.ScheduleLine, error) {
+	_, entries, payments, err := s.load(ctx, agreementID)
+	if err != nil {
+		return nil, err
+	}
+	return allocatePayments(entries, payments, today()), nil
+}
+
+// Ledger returns the running account of an agreement up to today
+func (s *rentService) Ledger(ctx context.Context, agreementID uint) (*dto.RentLedger, error) {
+	a, entries, payments, err := s.load(ctx, agreementID)
+	if err != nil {
+		return nil, err
+	}
+	asOf := today()
+	ledger := &dto.RentLedger{
+		AgreementID: a.AgreementID,
+		PropertyID:  a.PropertyID,
+		TenantName:  a.TenantName,
+		UnitNo:      a.UnitNo,
+		AsOf:        asOf,
+		Lines:       []dto.LedgerLine{},
+	}
+	var dueBeforeToday models.Money
+	for _, e := range entries {
+		due := utils.DateOf(e.DueDate)
+		if due.After(asOf) {
+			continue
+		}
+		ledger.TotalDue += e.Amount
+		if due.Before(asOf) {
+			dueBeforeToday += e.Amount
+		}
+		ledger.Lines = append(ledger.Lines, dto.LedgerLine{
+			Date:        due,
+			Description: fmt.Sprintf("Rent %s to %s", e.PeriodStart.Format(time.DateOnly), e.PeriodEnd.Format(time.DateOnly)),
+			Debit:       e.Amount,
+			ScheduleID:  e.ScheduleID,
+		})
+	}
+	for _, p := range payments {
+		ledger.TotalPaid += p.Amount
+		desc := "Payment"
+		if p.Mode != "" {
+			desc += " (" + p.Mode + ")"
+		}
+		if p.Reference != "" {
+			desc += " " + p.Reference
+		}
+		ledger.Lines = append(ledger.Lines, dto.LedgerLine{
+			Date:        utils.DateOf(p.PaidOn),
+			Description: desc,
+			Credit:      p.Amount,
+			PaymentID:   p.PaymentID,
+		})
+	}
+	// charges sort before payments made on the same day
+	sort.SliceStable(ledger.Lines, func(i, j int) bool {
+		li, lj := ledger.Lines[i], ledger.Lines[j]
+		if !li.Date.Equal(lj.Date) {
+			return li.Date.Before(lj.Date)
+		}
+		return li.Debit > 0 && lj.Debit == 0
+	})
+	var balance models.Money
+	for i := range ledger.Lines {
+		balance += ledger.Lines[i].Debit - ledger.Lines[i].Credit
+		ledger.Lines[i].Balance = balance
+	}
+	ledger.Balance = ledger.TotalDue - ledger.TotalPaid
+	ledger.Arrears = max(dueBeforeToday-ledger.TotalPaid, 0)
+	return ledger, nil
+}
+
+// AddPayment records rent received against an agreement
+func (s *rentService) AddPayment(ctx context.Context, p *models.RentPayment) error {
+	if p.Amount <= 0 {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidPaymentInput)
+	}
+	if p.PaidOn.After(time.Now()) {
+		return fmt.Errorf("%w: paid_on must not be in the future", ErrInvalidPaymentInput)
+	}
+	if p.Mode != "" && !slices.Contains(models.PaymentModes, p.Mode) {
+		return fmt.Errorf("%w: mode must be one of %s", ErrInvalidPaymentInput, strings.Join(models.PaymentModes, ", "))
+	}
+	if err := s.repo.AddPayment(ctx, p); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrAgreementNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Overdue lists agreements across the portfolio with rent unpaid past its due date
+func (s *rentService) Overdue(ctx context.Context, page repositories.Page) ([]dto.OverdueRent, repositories.PageInfo, error) {
+	asOf := today()
+	rows, info, err := s.repo.Overdue(ctx, asOf, page)
+	if err != nil {
+		if errors.Is(err, repositories.ErrCursorUnsupported) {
+			return nil, info, fmt.Errorf("%w: %v", ErrInvalidRentQuery, err)
+		}
+		return nil, info, err
+	}
+	for i := range rows {
+		rows[i].DaysOverdue = int(asOf.Sub(utils.DateOf(rows[i].OldestDueDate)).Hours() / 24)
+	}
+	return rows, info, nil
+}
+
+// EffectiveRent returns the monthly rent in effect on a date within the
+// agreement and the next escalation that changes it
+func (s *rentService) EffectiveRent(ctx context.Context, agreementID uint, on time.Time) (*dto.EffectiveRent, error) {
+	a, err := s.repo.Agreement(ctx, agreementID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrAgreementNotFound
+		}
+		return nil, err
+	}
+	day := utils.DateOf(on)
+	if day.Before(utils.DateOf(a.StartDate)) || day.After(utils.DateOf(a.EndDate)) {
+		return nil, fmt.Errorf("%w: date must be between %s and %s", ErrInvalidRentQuery,
+			a.StartDate.Format(time.DateOnly), a.EndDate.Format(time.DateOnly))
+	}
+	n := a.EscalationsBy(day)
+	rent := a.RentAfter(n)
+	out := &dto.EffectiveRent{
+		AgreementID:        a.AgreementID,
+		Date:               day,
+		BaseRent:           models.MoneyFromFloat(a.Rent),
+		MonthlyRent:        models.MoneyFromFloat(rent),
+		EscalationsApplied: n,
+	}
+	if !a.Escalates() {
+		return out, nil
+	}
+	// skip escalations held at the cap
+	end := utils.DateOf(a.EndDate)
+	for next := n + 1; !a.EscalationDate(next).After(end); next++ {
+		if nextRent := a.RentAfter(next); nextRent != rent {
+			date, amount := a.EscalationDate(next), models.MoneyFromFloat(nextRent)
+			out.NextEscalationDate, out.NextMonthlyRent = &date, &amount
+			break
+		}
+	}
+	return out, nil
+}
+
+func (s *rentService) load(ctx context.Context, agreementID uint) (*models.Agreement, []models.RentScheduleEntry, []models.RentPayment, error) {
+	a, err := s.repo.Agreement(ctx, agreementID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, nil, nil, ErrAgreementNotFound
+		}
+		return nil, nil, nil, err
+	}
+	entries, err := s.repo.Schedule(ctx, agreementID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	payments, err := s.repo.Payments(ctx, agreementID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return a, entries, payments, nil
+}
+
+// allocatePayments applies the total paid to the periods in due date order
+// and sets each period's status as of the given day
+func allocatePayments(entries []models.RentScheduleEntry, payments []models.RentPayment, asOf time.Time) []dto.ScheduleLine {
+	var remaining models.Money
+	for _, p := range payments {
+		remaining += p.Amount
+	}
+	lines := make([]dto.ScheduleLine, 0, len(entries))
+	for _, e := range entries {
+		paid := min(e.Amount, remaining)
+		remaining -= paid
+		line := dto.ScheduleLine{RentScheduleEntry: e, Paid: paid, Outstanding: e.Amount - paid}
+		due := utils.DateOf(e.DueDate)
+		switch {
+		case line.Outstanding == 0:
+			line.Status = dto.RentPaid
+		case due.Before(asOf):
+			line.Status = dto.RentOverdue
+		case paid > 0:
+			line.Status = dto.RentPartiallyPaid
+		case due.Equal(asOf):
+			line.Status = dto.RentDue
+		default:
+			line.Status = dto.RentUpcoming
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// today is midnight at the start of the current local day
+func today() time.Time {
+	return utils.DateOf(time.Now())
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"property-backend/dto"
+	"property-backend/models"
+)
+
+func TestAllocatePayments(t *testing.T) {
+	due := func(m time.Month) time.Time { return time.Date(2026, m, 1, 0, 0, 0, 0, time.Local) }
+	entries := []models.RentScheduleEntry{
+		{DueDate: due(time.January), Amount: 10000},
+		{DueDate: due(time.February), Amount: 10000},
+		{DueDate: due(time.March), Amount: 10000},
+	}
+	asOf := due(time.February)
+
+	for _, tc := range []struct {
+		name     string
+		payments []models.Money
+		paid     []models.Money
+		statuses []string
+	}{
+		{"nothing paid", nil,
+			[]models.Money{0, 0, 0}, []string{dto.RentOverdue, dto.RentDue, dto.RentUpcoming}},
+		{"part of an overdue period", []models.Money{4000},
+			[]models.Money{4000, 0, 0}, []string{dto.RentOverdue, dto.RentDue, dto.RentUpcoming}},
+		{"payments are added up", []models.Money{6000, 4000},
+			[]models.Money{10000, 0, 0}, []string{dto.RentPaid, dto.RentDue, dto.RentUpcoming}},
+		{"part of the period due today", []models.Money{15000},
+			[]models.Money{10000, 5000, 0}, []string{dto.RentPaid, dto.RentPartiallyPaid, dto.RentUpcoming}},
+		{"part of an upcoming period", []models.Money{25000},
+			[]models.Money{10000, 10000, 5000}, []string{dto.RentPaid, dto.RentPaid, dto.RentPartiallyPaid}},
+		{"everything paid", []models.Money{30000},
+			[]models.Money{10000, 10000, 10000}, []string{dto.RentPaid, dto.RentPaid, dto.RentPaid}},
+		{"over payment", []models.Money{20000, 20000},
+			[]models.Money{10000, 10000, 10000}, []string{dto.RentPaid, dto.RentPaid, dto.RentPaid}},
+	} {
+		var payments []models.RentPayment
+		for _, amount := range tc.payments {
+			payments = append(payments, models.RentPayment{Amount: amount})
+		}
+		lines := allocatePayments(entries, payments, asOf)
+		if len(lines) != len(entries) {
+			t.Fatalf("%s: %d lines, want %d", tc.name, len(lines), len(entries))
+		}
+		for i, l := range lines {
+			if l.Paid != tc.paid[i] || l.Outstanding != entries[i].Amount-tc.paid[i] || l.Status != tc.statuses[i] {
+				t.Errorf("%s: period %d paid %s outstanding %s %s, want paid %s %s",
+					tc.name, i, l.Paid, l.Outstanding, l.Status, tc.paid[i], tc.statuses[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"property-backend/models"
+	"property-backend/utils"
+)
+
+func TestNewAPIKeyStoresOnlyTheHash(t *testing.T) {
+	key, plain, err := newAPIKey(1, 2, "billing export", "rent:write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^pk_[0-9a-f]{8}\.[0-9a-f]{48}$`).MatchString(plain) {
+		t.Errorf("key %q is not pk_<8 hex>.<48 hex>", plain)
+	}
+	if !strings.HasPrefix(plain, key.Prefix+".") {
+		t.Errorf("prefix %q does not start key %q", key.Prefix, plain)
+	}
+	if key.KeyHash != utils.HashToken(plain) || strings.Contains(key.KeyHash, plain) {
+		t.Errorf("stored hash %q is not the hash of the key", key.KeyHash)
+	}
+	if key.UserID != 2 || key.CreatedBy != 1 || key.Name != "billing export" || key.Scopes != "rent:write" {
+		t.Errorf("key %+v", key)
+	}
+
+	_, other, err := newAPIKey(1, 2, "billing export", "rent:write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == plain {
+		t.Error("two keys generated with the same value")
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		scopes []Permission
+		ok     bool
+	}{
+		{"single scope", []Permission{PermPropertiesRead}, true},
+		{"every key scope", apiKeyPermissions, true},
+		{"no scopes", nil, false},
+		{"user administration", []Permission{PermPropertiesRead, PermUsersManage}, false},
+		{"unknown permission", []Permission{"properties:everything"}, false},
+	} {
+		err := validateScopes(tc.scopes)
+		if tc.ok && err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrInvalidScope) {
+			t.Errorf("%s: %v, want ErrInvalidScope", tc.name, err)
+		}
+	}
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore(models.User{UserID: 5, FirstName: "billing", IsActive: true, IsServiceAccount: true})
+	auth, _ := newFakeAuthService(store)
+	accounts := &serviceAccountService{users: fakeAuthRepo{s: store}, keys: fakeAPIKeyRepo{s: store}}
+
+	issued, err := accounts.CreateKey(ctx, 1, 5, "billing export", []Permission{PermRentWrite, PermAgreementsRead})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, scopes, err := auth.AuthenticateAPIKey(ctx, issued.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.UserID != 5 || !slices.Equal(scopes, []Permission{PermRentWrite, PermAgreementsRead}) {
+		t.Errorf("key resolved to user %d with scopes %v", user.UserID, scopes)
+	}
+	stored, _ := store.key(issued.Key.APIKeyID)
+	if stored.LastUsedAt == nil {
+		t.Fatal("last use not recorded")
+	}
+	firstUse := *stored.LastUsedAt
+	if _, _, err := auth.AuthenticateAPIKey(ctx, issued.Secret); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := store.key(issued.Key.APIKeyID); !stored.LastUsedAt.Equal(firstUse) {
+		t.Error("last use rewritten within a minute")
+	}
+	if _, _, err := auth.AuthenticateAPIKey(ctx, issued.Secret+"0"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("altered key: %v, want ErrInvalidToken", err)
+	}
+
+	rotated, err := accounts.RotateKey(ctx, 1, issued.Key.APIKeyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated.Key.Name != "billing export" || rotated.Key.Scopes != issued.Key.Scopes || rotated.Secret == issued.Secret {
+		t.Errorf("rotated key %+v", rotated.Key)
+	}
+	if _, _, err := auth.AuthenticateAPIKey(ctx, issued.Secret); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("key after rotation: %v, want ErrInvalidToken", err)
+	}
+	if _, err := accounts.RotateKey(ctx, 1, issued.Key.APIKeyID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("rotating a revoked key: %v, want ErrAPIKeyNotFound", err)
+	}
+
+	store.update(5, func(u *models.User) { u.IsActive = false })
+	if _, _, err := auth.AuthenticateAPIKey(ctx, rotated.Secret); !errors.Is(err, ErrAccountDisabled) {
+		t.Errorf("key of a deactivated account: %v, want ErrAccountDisabled", err)
+	}
+	store.update(5, func(u *models.User) { u.IsActive = true })
+
+	if err := accounts.RevokeKey(ctx, rotated.Key.APIKeyID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := auth.AuthenticateAPIKey(ctx, rotated.Secret); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("revoked key: %v, want ErrInvalidToken", err)
+	}
+	if err := accounts.RevokeKey(ctx, rotated.Key.APIKeyID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("revoking twice: %v, want ErrAPIKeyNotFound", err)
+	}
+}
+
+func TestCreateKeyOnlyForServiceAccounts(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeStore(models.User{UserID: 3, IsActive: true})
+	accounts := &serviceAccountService{users: fakeAuthRepo{s: store}, keys: fakeAPIKeyRepo{s: store}}
+	if _, err := accounts.CreateKey(ctx, 1, 3, "laptop", []Permission{PermPropertiesRead}); !errors.Is(err, ErrNotServiceAccount) {
+		t.Errorf("key for a person: %v, want ErrNotServiceAccount", err)
+	}
+	if len(store.apiKeys) != 0 {
+		t.Error("key stored for a person")
+	}
+}
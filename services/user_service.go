@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"property-backend/mailer"
+	"property-backend/models"
+	"property-backend/repositories"
+	"property-backend/utils"
+)
+
+// UserService defines user administration logic
+type UserService interface {
+	ListRoles(ctx context.Context) ([]models.RolesMaster, error)
+	GetUserRoles(ctx context.Context, userID uint) ([]models.RolesMaster, error)
+	GrantRole(ctx context.Context, userID uint, roleID uint, roleName string) (*models.RolesMaster, error)
+	RevokeRole(ctx context.Context, userID, roleID uint) error
+	InviteUser(ctx context.Context, u *models.User, roleIDs []uint) (int64, error)
+	UnlockUser(ctx context.Context, actorID, userID uint) error
+	ListUsers(ctx context.Context, filter repositories.UserFilter, page repositories.Page) ([]models.User, repositories.PageInfo, error)
+	GetUser(ctx context.Context, userID uint) (*models.User, error)
+	UpdateUser(ctx context.Context, userID uint, upd UserUpdate) (*models.User, error)
+	DeactivateUser(ctx context.Context, actorID, userID uint) error
+	ActivateUser(ctx context.Context, userID uint) error
+	ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error
+}
+
+// UserUpdate holds the profile fields that may be edited; nil fields are left unchanged
+type UserUpdate struct {
+	FirstName   *string
+	LastName    *string
+	PhoneNumber *string
+}
+
+type userService struct {
+	users    repositories.AuthRepository
+	roles    repositories.RoleRepository
+	tokens   repositories.UserTokenRepository
+	security repositories.SecurityRepository
+	mail     mailer.Sender
+}
+
+// NewUserService constructs a UserService
+func NewUserService(users repositories.AuthRepository, roles repositories.RoleRepository, tokens repositories.UserTokenRepository, security repositories.SecurityRepository, mail mailer.Sender) UserService {
+	return &userService{users: users, roles: roles, tokens: tokens, security: security, mail: mail}
+}
+
+func (s *userService) ListRoles(ctx context.Context) ([]models.RolesMaster, error) {
+	return s.roles.ListAll(ctx)
+}
+
+func (s *userService) GetUserRoles(ctx context.Context, userID uint) ([]models.RolesMaster, error) {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.roles.ListForUser(ctx, userID)
+}
+
+// GrantRole adds a role, identified by ID or by name, to the user
+func (s *userService) GrantRole(ctx context.Context, userID uint, roleID uint, roleName string) (*models.RolesMaster, error) {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	role, err := s.findRole(ctx, roleID, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.roles.Grant(ctx, userID, role.RolesMasterID); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *userService) RevokeRole(ctx context.Context, userID, roleID uint) error {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+	err := s.roles.Revoke(ctx, userID, roleID)
+	switch {
+	case errors.Is(err, repositories.ErrLastRole):
+		return ErrLastRole
+	case errors.Is(err, repositories.ErrLastAdmin):
+		return ErrLastAdmin
+	case errors.Is(err, repositories.ErrNotFound):
+		return ErrRoleNotFound
+	}
+	return err
+}
+
+// InviteUser creates an account on behalf of an administrator. The first role
+// becomes the primary role and the rest are granted alongside it. The account
+// starts with an unusable random password and the invitee receives a link to
+// choose their own.
+func (s *userService) InviteUser(ctx context.Context, u *models.User, roleIDs []uint) (int64, error) {
+	if len(roleIDs) == 0 {
+		return 0, ErrRoleNotFound
+	}
+	for _, roleID := range roleIDs {
+		if _, err := s.findRole(ctx, roleID, ""); err != nil {
+			return 0, err
+		}
+	}
+	if err := ensureEmailAvailable(ctx, s.users, u.Email); err != nil {
+		return 0, err
+	}
+
+	placeholder, err := utils.RandomToken(32)
+	if err != nil {
+		return 0, err
+	}
+	hashed, err := utils.HashPassword(placeholder)
+	if err != nil {
+		return 0, err
+	}
+	u.HashedPassword = hashed
+	u.RoleID = roleIDs[0]
+
+	id, err := s.users.CreateWithRoles(ctx, u, roleIDs[1:])
+	if err != nil {
+		return 0, err
+	}
+
+	token, err := issueUserToken(ctx, s.tokens, u.UserID, models.TokenPurposePasswordReset, invitationTokenTTL)
+	if err != nil {
+		return 0, err
+	}
+	if err := sendInvitationMail(ctx, s.mail, u, token); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UnlockUser clears a sign-in lockout and failure counter and records who did it
+func (s *userService) UnlockUser(ctx context.Context, actorID, userID uint) error {
+	user, err := s.users.GetByID(ctx, int64(userID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if err := s.users.ResetFailedLogins(ctx, userID); err != nil {
+		return err
+	}
+	return s.security.RecordAudit(ctx, &models.AuditLog{
+		EventType:   models.AuditAccountUnlocked,
+		UserID:      &user.UserID,
+		ActorUserID: &actorID,
+		Email:       user.Email,
+		Details:     "unlocked by administrator",
+	})
+}
+
+func (s *userService) ListUsers(ctx context.Context, filter repositories.UserFilter, page repositories.Page) ([]models.User, repositories.PageInfo, error) {
+	return s.users.List(ctx, filter, page)
+}
+
+func (s *userService) GetUser(ctx context.Context, userID uint) (*models.User, error) {
+	return getUser(ctx, s.users, userID)
+}
+
+func (s *userService) UpdateUser(ctx context.Context, userID uint, upd UserUpdate) (*models.User, error) {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if upd.FirstName != nil {
+		fields["first_name"] = *upd.FirstName
+	}
+	if upd.LastName != nil {
+		fields["last_name"] = *upd.LastName
+	}
+	if upd.PhoneNumber != nil {
+		fields["phone_number"] = *upd.PhoneNumber
+	}
+	if len(fields) > 0 {
+		if err := s.users.Update(ctx, userID, fields); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetUser(ctx, userID)
+}
+
+// DeactivateUser blocks sign in and token use for the user without deleting the row
+func (s *userService) DeactivateUser(ctx context.Context, actorID, userID uint) error {
+	if actorID == userID {
+		return ErrCannotDeactivateSelf
+	}
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+	return s.users.SetActive(ctx, userID, false)
+}
+
+func (s *userService) ActivateUser(ctx context.Context, userID uint) error {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+	return s.users.SetActive(ctx, userID, true)
+}
+
+func (s *userService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error {
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !utils.CheckPasswordHash(currentPassword, user.HashedPassword) {
+		return ErrInvalidCredentials
+	}
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	// bumping the token version signs out every session, this one included
+	return s.users.Update(ctx, userID, map[string]interface{}{
+		"hashed_password": hashed,
+		"token_version":   gorm.Expr("token_version + 1"),
+	})
+}
+
+func (s *userService) ensureUser(ctx context.Context, userID uint) error {
+	if _, err := s.users.GetByID(ctx, int64(userID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *userService) findRole(ctx context.Context, roleID uint, roleName string) (*models.RolesMaster, error) {
+	var (
+		role *models.RolesMaster
+		err  error
+	)
+	if roleID > 0 {
+		role, err = s.roles.GetByID(ctx, roleID)
+	} else {
+		role, err = s.roles.GetByName(ctx, roleName)
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	return role, err
+}
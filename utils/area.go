@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// AreaUnit is a unit clients can ask land and building areas to be reported in
+type AreaUnit string
+
+const (
+	AreaSquareMetre AreaUnit = "sqm"
+	AreaSquareFeet  AreaUnit = "sqft"
+	AreaAcre        AreaUnit = "acre"
+	AreaGunta       AreaUnit = "gunta"
+	AreaHectare     AreaUnit = "hectare"
+)
+
+// Square metres per unit. Karnataka land records use 40 gunta to the acre.
+const (
+	SquareMetresPerAcre       = 4046.8564224
+	SquareMetresPerGunta      = SquareMetresPerAcre / 40
+	SquareMetresPerHectare    = 10000.0
+	SquareMetresPerSquareFoot = 0.09290304
+)
+
+var squareMetresPer = map[AreaUnit]float64{
+	AreaSquareMetre: 1,
+	AreaSquareFeet:  SquareMetresPerSquareFoot,
+	AreaAcre:        SquareMetresPerAcre,
+	AreaGunta:       SquareMetresPerGunta,
+	AreaHectare:     SquareMetresPerHectare,
+}
+
+var areaUnitAliases = map[string]AreaUnit{
+	"acres":  AreaAcre,
+	"gunte":  AreaGunta,
+	"guntas": AreaGunta,
+	"guntha": AreaGunta,
+	"ha":     AreaHectare,
+	"sq.ft":  AreaSquareFeet,
+	"sq_ft":  AreaSquareFeet,
+	"m2":     AreaSquareMetre,
+	"sq.m":   AreaSquareMetre,
+}
+
+// ErrInvalidAreaUnit is returned by ParseAreaUnit for an unknown unit
+var ErrInvalidAreaUnit = errors.New("invalid area unit, expected acre, gunta, hectare, sqft or sqm")
+
+// ParseAreaUnit reads a unit name case-insensitively, accepting common
+// spellings such as "gunte" and "ha". An empty string means square metres.
+func ParseAreaUnit(s string) (AreaUnit, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return AreaSquareMetre, nil
+	}
+	if u, ok := areaUnitAliases[s]; ok {
+		return u, nil
+	}
+	if _, ok := squareMetresPer[AreaUnit(s)]; ok {
+		return AreaUnit(s), nil
+	}
+	return "", ErrInvalidAreaUnit
+}
+
+// ToSquareMetres converts an area in the given unit to square metres
+func ToSquareMetres(v float64, from AreaUnit) float64 {
+	return v * squareMetresPer[from]
+}
+
+// FromSquareMetres converts square metres to the given unit, rounded to four
+// decimal places
+func FromSquareMetres(sqm float64, to AreaUnit) float64 {
+	return math.Round(sqm/squareMetresPer[to]*1e4) / 1e4
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate is returned by ParseDate for anything but a real calendar
+// date in one of DateLayouts
+var ErrInvalidDate = errors.New("invalid date, expected YYYY-MM-DD or DD-MM-YYYY")
+
+// DateLayouts are the date formats accepted from clients: ISO 8601 and the
+// day-first form used on Indian documents
+var DateLayouts = []string{time.DateOnly, "02-01-2006"}
+
+// ParseDate reads a calendar date as midnight local time. Parsing is strict:
+// both digits of the day and month are required and impossible dates such as
+// 31-02-2025 are rejected.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range DateLayouts {
+		if len(s) != len(layout) {
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidDate
+}
+
+// DateOf is midnight local time on t's calendar date. Values read from date
+// columns arrive at midnight UTC; taking the date in t's own location keeps
+// their calendar day, so they compare cleanly with ParseDate results and today.
+func DateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// AddMonths moves a date n months on, keeping its day of the month where
+// possible and otherwise using the last day (31 Jan + 1 month = 28 Feb)
+func AddMonths(t time.Time, n int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, t.Location())
+}
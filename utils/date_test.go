@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateOfKeepsCalendarDay(t *testing.T) {
+	want := time.Date(2025, 4, 1, 0, 0, 0, 0, time.Local)
+	for _, in := range []time.Time{
+		time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), // as read from a date column
+		time.Date(2025, 4, 1, 23, 59, 0, 0, time.Local),
+		time.Date(2025, 4, 1, 18, 30, 0, 0, time.FixedZone("UTC-10", -10*3600)),
+	} {
+		if got := DateOf(in); !got.Equal(want) {
+			t.Errorf("DateOf(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
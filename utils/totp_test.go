@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the RFC 6238 SHA-1 test key "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTPRFC6238Vectors(t *testing.T) {
+	// the RFC lists 8 digit codes; a 6 digit code is their last six digits
+	for _, tc := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	} {
+		at := time.Unix(tc.unix, 0)
+		step, ok := ValidateTOTP(rfcSecret, tc.code, at)
+		if !ok {
+			t.Errorf("T=%d: code %s rejected", tc.unix, tc.code)
+			continue
+		}
+		if want := tc.unix / totpPeriod; step != want {
+			t.Errorf("T=%d: step %d, want %d", tc.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPSkewWindow(t *testing.T) {
+	key, _ := totpEncoding.DecodeString(rfcSecret)
+	at := time.Unix(1234567890, 0)
+	current := at.Unix() / totpPeriod
+	for _, tc := range []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{"two steps behind", -2, false},
+		{"one step behind", -1, true},
+		{"current step", 0, true},
+		{"one step ahead", 1, true},
+		{"two steps ahead", 2, false},
+	} {
+		step, ok := ValidateTOTP(rfcSecret, totpCode(key, current+tc.offset), at)
+		if ok != tc.ok {
+			t.Errorf("%s: accepted=%t, want %t", tc.name, ok, tc.ok)
+		}
+		if ok && step != current+tc.offset {
+			t.Errorf("%s: step %d, want %d", tc.name, step, current+tc.offset)
+		}
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	at := time.Unix(59, 0)
+	for _, tc := range []struct {
+		name, secret, code string
+		ok                 bool
+	}{
+		{"lower case secret", strings.ToLower(rfcSecret), "287082", true},
+		{"wrong code", rfcSecret, "287083", false},
+		{"too short", rfcSecret, "28708", false},
+		{"too long", rfcSecret, "2870820", false},
+		{"empty code", rfcSecret, "", false},
+		{"invalid secret", "not-base32!", "287082", false},
+		{"empty secret", "", "287082", false},
+	} {
+		if _, ok := ValidateTOTP(tc.secret, tc.code, at); ok != tc.ok {
+			t.Errorf("%s: accepted=%t, want %t", tc.name, ok, tc.ok)
+		}
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	a, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateTOTPSecret()
+	if a == b {
+		t.Error("two secrets are identical")
+	}
+	key, err := totpEncoding.DecodeString(a)
+	if err != nil || len(key) != 20 {
+		t.Errorf("secret %q decodes to %d bytes (%v), want 20", a, len(key), err)
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	u, err := url.Parse(TOTPProvisioningURI("Property Backend", "asha@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Property Backend:asha@example.com" {
+		t.Errorf("unexpected URI %s", u)
+	}
+	q := u.Query()
+	for k, want := range map[string]string{"secret": rfcSecret, "issuer": "Property Backend", "digits": "6", "period": "30", "algorithm": "SHA1"} {
+		if got := q.Get(k); got != want {
+			t.Errorf("%s = %q, want %q", k, got, want)
+		}
+	}
+}